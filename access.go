@@ -0,0 +1,551 @@
+package go-sharefile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// OData types the API reports for principals.
+const (
+	TypeUser  = "ShareFile.Api.Models.User"
+	TypeGroup = "ShareFile.Api.Models.Group"
+)
+
+// Principal is the user or group an access control entry applies to. Name is a user's full name or a group's name.
+type Principal struct {
+	ID    string `json:"Id"`
+	Type  string `json:"odata.type"`
+	Email string `json:"Email"`
+	Name  string `json:"Name"`
+}
+
+// IsGroup reports whether the principal is a group.
+func (p *Principal) IsGroup() bool {
+	return p.Type == TypeGroup
+}
+
+// AccessControl is an entry of an item's access control list, granting one principal its permissions on the item.
+// Inherited entries come from a parent folder rather than being set on the item itself. Notified isn't part of the API:
+// GrantAccess sets it when ShareFile emailed the principal about the entry.
+type AccessControl struct {
+	Principal            *Principal `json:"Principal"`
+	CanUpload            bool       `json:"CanUpload"`
+	CanDownload          bool       `json:"CanDownload"`
+	CanView              bool       `json:"CanView"`
+	CanDelete            bool       `json:"CanDelete"`
+	CanManagePermissions bool       `json:"CanManagePermissions"`
+	NotifyOnUpload       bool       `json:"NotifyOnUpload"`
+	NotifyOnDownload     bool       `json:"NotifyOnDownload"`
+	Inherited            bool       `json:"IsInherited"`
+	Notified             bool       `json:"-"`
+}
+
+// Collection response wrapping a list of access control entries, internal package use.
+type accessControlFeed struct {
+	Entries []AccessControl `json:"value"`
+}
+
+// AccessControls returns the access control list of an item, fetching as many pages as needed. Sorting isn't
+// supported.
+func AccessControls(ctx context.Context, itemID string, opts ListOptions) ([]AccessControl, error) {
+	uriPath := fmt.Sprintf("/sf/v3/Items(%s)/AccessControls", itemID)
+
+	entries := []AccessControl{}
+	err := opts.pageAll("access controls", func(q *Query) (int, error) {
+		var feed accessControlFeed
+		if err := call(ctx, "GET", withQuery(uriPath, q.Expand("Principal")), nil, &feed); err != nil {
+			return 0, err
+		}
+		entries = append(entries, feed.Entries...)
+		return len(feed.Entries), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// Permissions are the rights an access control entry grants.
+type Permissions struct {
+	CanView              bool
+	CanDownload          bool
+	CanUpload            bool
+	CanDelete            bool
+	CanManagePermissions bool
+	// CanShare is only reported by MyPermissions; access control entries don't carry it.
+	CanShare bool
+}
+
+// Struct for use in access control POST/PATCH activities
+type accessControlBody struct {
+	Principal            *principalRef `json:",omitempty"`
+	CanView              bool
+	CanDownload          bool
+	CanUpload            bool
+	CanDelete            bool
+	CanManagePermissions bool
+}
+
+// GrantOption configures GrantAccess.
+type GrantOption func(*grantOptions)
+
+// Options collected from GrantOption values, internal package use.
+type grantOptions struct {
+	createClient bool
+}
+
+// WithCreateClient makes GrantAccess create a client user for an email address that doesn't belong to a user yet.
+func WithCreateClient() GrantOption {
+	return func(o *grantOptions) {
+		o.createClient = true
+	}
+}
+
+// GrantAccess gives a user or group the permissions on a folder and returns the resulting access control entry. The
+// principal is a user or group ID, or an email address, which is looked up with UserByEmail. A principal that already
+// has an entry on the folder gets it updated. With notify set, ShareFile emails the principal that the folder was
+// shared with them, adding customMessage when it isn't empty. ShareFile only sends that email for new entries, so an
+// updated entry comes back with Notified unset even when notify was asked for.
+func GrantAccess(ctx context.Context, folderID, principalID string, perms Permissions, notify bool, customMessage string, opts ...GrantOption) (*AccessControl, error) {
+	o := &grantOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if strings.Contains(principalID, "@") {
+		id, _, err := resolveEmail(ctx, principalID, o.createClient)
+		if err != nil {
+			return nil, err
+		}
+		principalID = id
+	}
+
+	body := accessControlBody{
+		Principal:            &principalRef{ID: principalID},
+		CanView:              perms.CanView,
+		CanDownload:          perms.CanDownload,
+		CanUpload:            perms.CanUpload,
+		CanDelete:            perms.CanDelete,
+		CanManagePermissions: perms.CanManagePermissions,
+	}
+
+	params := url.Values{}
+	params.Set("sendDefaultNotification", strconv.FormatBool(notify))
+	if notify && customMessage != "" {
+		params.Set("message", customMessage)
+	}
+
+	var entry AccessControl
+	err := call(ctx, "POST", fmt.Sprintf("/sf/v3/Items(%s)/AccessControls?%s", folderID, params.Encode()), body, &entry)
+	if err == nil {
+		entry.Notified = notify
+		return &entry, nil
+	}
+	if !errors.Is(err, ErrConflict) {
+		return nil, err
+	}
+
+	// The update carries no notification parameters; ShareFile has none for existing entries.
+	body.Principal = nil
+	if err := call(ctx, "PATCH", accessControlPath(folderID, principalID), body, &entry); err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// Returns the path of the access control entry of a principal on an item, internal package use.
+func accessControlPath(itemID, principalID string) string {
+	return fmt.Sprintf("/sf/v3/AccessControls(principalid=%s,itemid=%s)", principalID, itemID)
+}
+
+// Returns the ID of the user with an email address, creating a client user for it if asked to, and whether it did,
+// internal package use. A client created by someone else in the meantime is looked up rather than reported.
+func resolveEmail(ctx context.Context, email string, createClient bool) (string, bool, error) {
+	user, err := UserByEmail(ctx, email)
+	if err == nil {
+		return user.ID, false, nil
+	}
+	if !errors.Is(err, ErrNotFound) || !createClient {
+		return "", false, err
+	}
+
+	user, err = CreateClient(ctx, ClientUser{Email: email})
+	var exists *UserExistsError
+	if errors.As(err, &exists) {
+		if exists.ID != "" {
+			return exists.ID, false, nil
+		}
+		user, err = UserByEmail(ctx, email)
+		if err != nil {
+			return "", false, fmt.Errorf("sharefile: user %s exists but can't be looked up: %w", email, err)
+		}
+		return user.ID, false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	return user.ID, true, nil
+}
+
+// Returns the permissions an access control entry grants, internal package use.
+func (ac *AccessControl) permissions() Permissions {
+	return Permissions{
+		CanView:              ac.CanView,
+		CanDownload:          ac.CanDownload,
+		CanUpload:            ac.CanUpload,
+		CanDelete:            ac.CanDelete,
+		CanManagePermissions: ac.CanManagePermissions,
+	}
+}
+
+// ACLDiff is the outcome of SetAccessControls: the entries it added, changed and removed, and the principal IDs of
+// those it failed to apply with their errors.
+type ACLDiff struct {
+	Added   []AccessControl
+	Updated []AccessControl
+	Removed []AccessControl
+	Failed  map[string]error
+}
+
+// Struct for use in bulk access control POST activities
+type bulkAccessControlBody struct {
+	NotifyUser          bool
+	AccessControlParams []accessControlParam
+}
+
+// Struct for use in bulk access control POST activities, one per entry
+type accessControlParam struct {
+	AccessControl fullAccessControlBody
+	NotifyUser    bool
+}
+
+// SetAccessControls brings the access control list of a folder in line with desired, in which each entry's Principal
+// needs only its ID. Principals with no entry are added and those whose permissions or notification flags differ are
+// updated; with removeOthers set, entries set on the folder for principals not in desired are removed, while
+// inherited entries are always left alone. No one is emailed about the changes. The changes go in one bulk request for
+// the additions and updates and one for the removals; when a bulk request fails its changes are made one call each,
+// so a failed one doesn't stop the others: the diff lists what was applied, and the returned error joins the
+// failures.
+func SetAccessControls(ctx context.Context, folderID string, desired []AccessControl, removeOthers bool) (*ACLDiff, error) {
+	current, err := AccessControls(ctx, folderID, ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]AccessControl, len(current))
+	for _, ac := range current {
+		if ac.Principal != nil {
+			existing[ac.Principal.ID] = ac
+		}
+	}
+
+	var changes []AccessControl
+	wanted := make(map[string]bool, len(desired))
+	for _, ac := range desired {
+		if ac.Principal == nil || ac.Principal.ID == "" {
+			return nil, fmt.Errorf("sharefile: access control entry without a principal ID")
+		}
+		wanted[ac.Principal.ID] = true
+
+		if cur, ok := existing[ac.Principal.ID]; !ok || !cur.sameAccess(&ac) {
+			changes = append(changes, ac)
+		}
+	}
+
+	var removals []AccessControl
+	if removeOthers {
+		for _, ac := range current {
+			if ac.Principal != nil && !ac.Inherited && !wanted[ac.Principal.ID] {
+				removals = append(removals, ac)
+			}
+		}
+	}
+
+	diff := &ACLDiff{Failed: map[string]error{}}
+	applied := func(ac AccessControl) {
+		if _, ok := existing[ac.Principal.ID]; ok {
+			diff.Updated = append(diff.Updated, ac)
+		} else {
+			diff.Added = append(diff.Added, ac)
+		}
+	}
+
+	if len(changes) > 0 {
+		body := bulkAccessControlBody{AccessControlParams: make([]accessControlParam, len(changes))}
+		for i := range changes {
+			body.AccessControlParams[i].AccessControl = changes[i].body()
+		}
+		err := call(ctx, "POST", fmt.Sprintf("/sf/v3/Items(%s)/AccessControls/BulkSet", folderID), body, nil)
+		for _, ac := range changes {
+			if err != nil {
+				if err := setAccessControl(ctx, folderID, ac, existing); err != nil {
+					diff.Failed[ac.Principal.ID] = err
+					continue
+				}
+			}
+			applied(ac)
+		}
+	}
+
+	if len(removals) > 0 {
+		ids := make([]string, len(removals))
+		for i, ac := range removals {
+			ids[i] = ac.Principal.ID
+		}
+		err := call(ctx, "POST", fmt.Sprintf("/sf/v3/Items(%s)/AccessControls/BulkDelete", folderID), ids, nil)
+		for _, ac := range removals {
+			if err != nil {
+				if err := call(ctx, "DELETE", accessControlPath(folderID, ac.Principal.ID), nil, nil); err != nil {
+					diff.Failed[ac.Principal.ID] = err
+					continue
+				}
+			}
+			diff.Removed = append(diff.Removed, ac)
+		}
+	}
+
+	ids := make([]string, 0, len(diff.Failed))
+	for id := range diff.Failed {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	errs := make([]error, 0, len(ids))
+	for _, id := range ids {
+		errs = append(errs, fmt.Errorf("principal %s: %w", id, diff.Failed[id]))
+	}
+
+	return diff, errors.Join(errs...)
+}
+
+// Sets one access control entry of a folder without notifying anyone, updating the principal's own entry when it has
+// one and adding one otherwise, internal package use.
+func setAccessControl(ctx context.Context, folderID string, ac AccessControl, existing map[string]AccessControl) error {
+	body := ac.body()
+	if cur, ok := existing[ac.Principal.ID]; ok && !cur.Inherited {
+		body.Principal = nil
+		return call(ctx, "PATCH", accessControlPath(folderID, ac.Principal.ID), body, nil)
+	}
+
+	// A failed bulk request may still have added the entry.
+	err := call(ctx, "POST", fmt.Sprintf("/sf/v3/Items(%s)/AccessControls?sendDefaultNotification=false", folderID), body, nil)
+	if errors.Is(err, ErrConflict) {
+		body.Principal = nil
+		err = call(ctx, "PATCH", accessControlPath(folderID, ac.Principal.ID), body, nil)
+	}
+	return err
+}
+
+// Reports whether two access control entries grant the same permissions and notifications, internal package use.
+func (ac *AccessControl) sameAccess(other *AccessControl) bool {
+	return ac.permissions() == other.permissions() &&
+		ac.NotifyOnUpload == other.NotifyOnUpload &&
+		ac.NotifyOnDownload == other.NotifyOnDownload
+}
+
+// Returns the request body setting an access control entry, internal package use.
+func (ac *AccessControl) body() fullAccessControlBody {
+	return fullAccessControlBody{
+		accessControlBody: accessControlBody{
+			Principal:            &principalRef{ID: ac.Principal.ID},
+			CanView:              ac.CanView,
+			CanDownload:          ac.CanDownload,
+			CanUpload:            ac.CanUpload,
+			CanDelete:            ac.CanDelete,
+			CanManagePermissions: ac.CanManagePermissions,
+		},
+		notificationBody: notificationBody{NotifyOnUpload: ac.NotifyOnUpload, NotifyOnDownload: ac.NotifyOnDownload},
+	}
+}
+
+// Struct for use in access control notification PATCH activities
+type notificationBody struct {
+	NotifyOnUpload   bool
+	NotifyOnDownload bool
+}
+
+// Struct for use in access control POST/PATCH activities that set notifications along with permissions
+type fullAccessControlBody struct {
+	accessControlBody
+	notificationBody
+}
+
+// SetNotification sets whether the folder's owner is emailed when a principal uploads to or downloads from a folder,
+// leaving the principal's permissions as they are. When the principal's access is inherited from a parent folder, the
+// folder gets an entry of its own with the same permissions and the new flags, overriding the inherited one; the
+// boolean reports that this happened. A principal without access gets an error wrapping ErrNotFound.
+func SetNotification(ctx context.Context, folderID, principalID string, notifyUpload, notifyDownload bool) (bool, error) {
+	cur, err := principalEntry(ctx, folderID, principalID)
+	if err != nil {
+		return false, err
+	}
+
+	notify := notificationBody{NotifyOnUpload: notifyUpload, NotifyOnDownload: notifyDownload}
+	if !cur.Inherited {
+		return false, call(ctx, "PATCH", accessControlPath(folderID, principalID), notify, nil)
+	}
+
+	override := cur.body()
+	override.notificationBody = notify
+	if err := call(ctx, "POST", fmt.Sprintf("/sf/v3/Items(%s)/AccessControls", folderID), override, nil); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Returns the access control entry of a principal on an item, own or inherited, internal package use. A principal
+// without one gets an error wrapping ErrNotFound.
+func principalEntry(ctx context.Context, itemID, principalID string) (*AccessControl, error) {
+	entries, err := AccessControls(ctx, itemID, ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range entries {
+		if entries[i].Principal != nil && entries[i].Principal.ID == principalID {
+			return &entries[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("sharefile: principal %s has no access to item %s: %w", principalID, itemID, ErrNotFound)
+}
+
+// MyPermissions returns what the current user can do with an item, from the item's capabilities and the user's own
+// access control entry on it. Nothing is cached, so the answer is current as of the call.
+func MyPermissions(ctx context.Context, itemID string) (Permissions, error) {
+	info, err := GetItemInfo(ctx, itemID)
+	if err != nil {
+		return Permissions{}, err
+	}
+
+	perms := Permissions{
+		CanView:              info.CanView,
+		CanDownload:          info.CanDownload,
+		CanUpload:            info.CanUpload,
+		CanDelete:            info.CanDeleteCurrentItem,
+		CanManagePermissions: info.CanManagePermissions,
+		CanShare:             info.CanShare,
+	}
+
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return Permissions{}, err
+	}
+	// Items the user owns, or reaches only through a group, have no entry of the user's own.
+	entry, err := principalEntry(ctx, itemID, userID)
+	if errors.Is(err, ErrNotFound) || errors.Is(err, ErrForbidden) {
+		return perms, nil
+	}
+	if err != nil {
+		return Permissions{}, err
+	}
+
+	own := entry.permissions()
+	perms.CanView = perms.CanView || own.CanView
+	perms.CanDownload = perms.CanDownload || own.CanDownload
+	perms.CanUpload = perms.CanUpload || own.CanUpload
+	perms.CanDelete = perms.CanDelete || own.CanDelete
+	perms.CanManagePermissions = perms.CanManagePermissions || own.CanManagePermissions
+
+	return perms, nil
+}
+
+// PermissionsFor returns the permissions a user or group's access control entry grants on an item, for
+// administrators reviewing someone else's access. Access a user has only through a group isn't included. A principal
+// without an entry gets an error wrapping ErrNotFound.
+func PermissionsFor(ctx context.Context, itemID, principalID string) (Permissions, error) {
+	entry, err := principalEntry(ctx, itemID, principalID)
+	if err != nil {
+		return Permissions{}, err
+	}
+
+	return entry.permissions(), nil
+}
+
+// Role is a preset of permissions for InviteToFolder.
+type Role string
+
+// Roles InviteToFolder grants.
+const (
+	// RoleViewer can view and download.
+	RoleViewer Role = "viewer"
+	// RoleCollaborator can also upload.
+	RoleCollaborator Role = "collaborator"
+	// RoleFullAccess can also delete and manage the folder's permissions.
+	RoleFullAccess Role = "fullaccess"
+)
+
+// Returns the permissions of a role, internal package use.
+func (r Role) permissions() (Permissions, error) {
+	switch r {
+	case RoleViewer:
+		return Permissions{CanView: true, CanDownload: true}, nil
+	case RoleCollaborator:
+		return Permissions{CanView: true, CanDownload: true, CanUpload: true}, nil
+	case RoleFullAccess:
+		return Permissions{CanView: true, CanDownload: true, CanUpload: true, CanDelete: true, CanManagePermissions: true}, nil
+	}
+	return Permissions{}, fmt.Errorf("sharefile: unknown role %q", r)
+}
+
+// InviteResult is the outcome of InviteToFolder for one email address. CreatedUser reports that a client user was
+// created for the address; otherwise the address belonged to an existing user. Notified reports that the user was
+// emailed, which isn't the case for users who already had an entry on the folder. Err is set when the invitation
+// failed.
+type InviteResult struct {
+	Email       string
+	UserID      string
+	CreatedUser bool
+	Notified    bool
+	Err         error
+}
+
+// InviteToFolder gives the users with the given email addresses the permissions of a role on a folder and sends each
+// ShareFile's notification email with message added. Users who already have an entry on the folder get it updated to
+// the role but aren't emailed, as ShareFile only notifies about new entries. Addresses without a user become client
+// users when createClients is set, and fail otherwise. A failed address doesn't stop the others: there is a result for
+// every address in the order given, and the returned error joins the failures.
+func InviteToFolder(ctx context.Context, folderID string, emails []string, role Role, message string, createClients bool) ([]InviteResult, error) {
+	perms, err := role.permissions()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]InviteResult, len(emails))
+	var errs []error
+	for i, email := range emails {
+		r := &results[i]
+		r.Email = email
+		r.Err = invite(ctx, folderID, r, perms, message, createClients)
+		if r.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", email, r.Err))
+		}
+	}
+
+	return results, errors.Join(errs...)
+}
+
+// Invites one email address to a folder, filling in its result, internal package use.
+func invite(ctx context.Context, folderID string, r *InviteResult, perms Permissions, message string, createClients bool) error {
+	id, created, err := resolveEmail(ctx, r.Email, createClients)
+	if err != nil {
+		return err
+	}
+	r.UserID, r.CreatedUser = id, created
+
+	entry, err := GrantAccess(ctx, folderID, id, perms, true, message)
+	if err != nil {
+		return err
+	}
+	r.Notified = entry.Notified
+
+	return nil
+}
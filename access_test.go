@@ -0,0 +1,272 @@
+package go-sharefile
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestInviteToFolder(t *testing.T) {
+	// new@ has no user and is created; raced@ is created by someone else between the lookup and the create, and only
+	// shows up in lookups a moment after the conflict. member@ already has an entry on the folder, so it is updated
+	// and not emailed.
+	var mu sync.Mutex
+	users := map[string]string{"known@example.com": "u1", "member@example.com": "u4"}
+	lookups := map[string]int{}
+	var granted []string
+	fakeAPI(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/sf/v3/Users":
+			email := r.URL.Query().Get("emailaddress")
+			lookups[email]++
+			if email == "raced@example.com" && lookups[email] == 3 {
+				users[email] = "u3"
+			}
+			if id, ok := users[email]; ok {
+				writeJSON(t, w, map[string]string{"Id": id, "Email": email})
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == "POST" && r.URL.Path == "/sf/v3/Users":
+			var body userBody
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Error(err)
+			}
+			if body.Email == "new@example.com" {
+				users[body.Email] = "u2"
+				writeJSON(t, w, map[string]string{"Id": "u2", "Email": "new@example.com"})
+				return
+			}
+			w.WriteHeader(http.StatusConflict)
+		case r.Method == "POST" && r.URL.Path == "/sf/v3/Items(fo1)/AccessControls":
+			if got := r.URL.Query().Get("sendDefaultNotification"); got != "true" {
+				t.Errorf("sendDefaultNotification is %q", got)
+			}
+			if got := r.URL.Query().Get("message"); got != "welcome aboard" {
+				t.Errorf("message is %q", got)
+			}
+			var body accessControlBody
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Error(err)
+			}
+			granted = append(granted, body.Principal.ID)
+			if body.Principal.ID == "u4" {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			writeJSON(t, w, map[string]string{})
+		case r.Method == "PATCH" && r.URL.Path == "/sf/v3/AccessControls(principalid=u4,itemid=fo1)":
+			granted = append(granted, "u4 updated")
+			writeJSON(t, w, map[string]string{})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	emails := []string{"known@example.com", "new@example.com", "raced@example.com", "member@example.com"}
+	results, err := InviteToFolder(context.Background(), "fo1", emails, RoleViewer, "welcome aboard", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []InviteResult{
+		{Email: "known@example.com", UserID: "u1", Notified: true},
+		{Email: "new@example.com", UserID: "u2", CreatedUser: true, Notified: true},
+		{Email: "raced@example.com", UserID: "u3", Notified: true},
+		{Email: "member@example.com", UserID: "u4"},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("%d results, want %d", len(results), len(want))
+	}
+	for i, r := range results {
+		if r != want[i] {
+			t.Errorf("result %d is %+v, want %+v", i, r, want[i])
+		}
+	}
+	if fmt.Sprint(granted) != "[u1 u2 u3 u4 u4 updated]" {
+		t.Errorf("access granted to %v", granted)
+	}
+}
+
+func TestGrantAccessExisting(t *testing.T) {
+	// u1 has no entry yet and is emailed; u2 already has one, which is updated without an email.
+	var patched []string
+	fakeAPI(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "POST" && r.URL.Path == "/sf/v3/Items(fo1)/AccessControls":
+			if got := r.URL.Query().Get("sendDefaultNotification"); got != "true" {
+				t.Errorf("sendDefaultNotification is %q", got)
+			}
+			if got := r.URL.Query().Get("message"); got != "welcome" {
+				t.Errorf("message is %q", got)
+			}
+			var body accessControlBody
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Error(err)
+			}
+			if body.Principal.ID == "u2" {
+				w.WriteHeader(http.StatusConflict)
+				return
+			}
+			writeJSON(t, w, map[string]interface{}{"Principal": map[string]string{"Id": body.Principal.ID}, "CanView": true})
+		case r.Method == "PATCH" && r.URL.Path == "/sf/v3/AccessControls(principalid=u2,itemid=fo1)":
+			var body accessControlBody
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Error(err)
+			}
+			if body.Principal != nil || !body.CanView {
+				t.Errorf("update body is %+v", body)
+			}
+			patched = append(patched, "u2")
+			writeJSON(t, w, map[string]interface{}{"Principal": map[string]string{"Id": "u2"}, "CanView": true})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	perms := Permissions{CanView: true}
+	entry, err := GrantAccess(context.Background(), "fo1", "u1", perms, true, "welcome")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !entry.Notified {
+		t.Error("new entry not reported as notified")
+	}
+
+	entry, err = GrantAccess(context.Background(), "fo1", "u2", perms, true, "welcome")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Notified {
+		t.Error("updated entry reported as notified")
+	}
+	if len(patched) != 1 {
+		t.Errorf("entry updated %d times", len(patched))
+	}
+}
+
+func TestSetAccessControls(t *testing.T) {
+	// u1 only needs its upload notification turned on and u2 is already as desired; g1's entry is inherited and u3's
+	// isn't wanted. u4 is new, and u5 is refused when set on its own.
+	current := []map[string]interface{}{
+		{"Principal": map[string]string{"Id": "u1"}, "CanView": true, "CanDownload": true},
+		{"Principal": map[string]string{"Id": "u2"}, "CanView": true},
+		{"Principal": map[string]string{"Id": "g1"}, "CanView": true, "IsInherited": true},
+		{"Principal": map[string]string{"Id": "u3"}, "CanView": true},
+	}
+	desired := []AccessControl{
+		{Principal: &Principal{ID: "u1"}, CanView: true, CanDownload: true, NotifyOnUpload: true},
+		{Principal: &Principal{ID: "u2"}, CanView: true},
+		{Principal: &Principal{ID: "u4"}, CanView: true, CanUpload: true},
+		{Principal: &Principal{ID: "u5"}, CanView: true},
+	}
+
+	for _, bulk := range []bool{true, false} {
+		var calls []string
+		fakeAPI(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			call := r.Method + " " + r.URL.Path
+			switch call {
+			case "GET /sf/v3/Items(fo1)/AccessControls":
+				writeJSON(t, w, map[string]interface{}{"value": current})
+				return
+			case "POST /sf/v3/Items(fo1)/AccessControls/BulkSet":
+				var body bulkAccessControlBody
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Error(err)
+				}
+				var ids []string
+				for _, p := range body.AccessControlParams {
+					ids = append(ids, p.AccessControl.Principal.ID)
+					if p.NotifyUser {
+						t.Errorf("bulk set notifies %s", p.AccessControl.Principal.ID)
+					}
+				}
+				call += fmt.Sprint(ids, body.AccessControlParams[0].AccessControl.NotifyOnUpload)
+			case "POST /sf/v3/Items(fo1)/AccessControls/BulkDelete":
+				var ids []string
+				if err := json.NewDecoder(r.Body).Decode(&ids); err != nil {
+					t.Error(err)
+				}
+				call += fmt.Sprint(ids)
+			case "POST /sf/v3/Items(fo1)/AccessControls":
+				if got := r.URL.Query().Get("sendDefaultNotification"); got != "false" {
+					t.Errorf("sendDefaultNotification is %q", got)
+				}
+				var body fullAccessControlBody
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Error(err)
+				}
+				call += " " + body.Principal.ID
+				if body.Principal.ID == "u5" {
+					w.WriteHeader(http.StatusForbidden)
+					calls = append(calls, call)
+					return
+				}
+			case "PATCH /sf/v3/AccessControls(principalid=u1,itemid=fo1)":
+				var body fullAccessControlBody
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Error(err)
+				}
+				if body.Principal != nil || !body.CanDownload || !body.NotifyOnUpload {
+					t.Errorf("update body is %+v", body)
+				}
+			case "DELETE /sf/v3/AccessControls(principalid=u3,itemid=fo1)":
+			default:
+				http.NotFound(w, r)
+				return
+			}
+			calls = append(calls, call)
+			if !bulk && strings.Contains(call, "Bulk") {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}))
+
+		diff, err := SetAccessControls(context.Background(), "fo1", desired, true)
+
+		principals := func(entries []AccessControl) []string {
+			ids := []string{}
+			for _, ac := range entries {
+				ids = append(ids, ac.Principal.ID)
+			}
+			return ids
+		}
+		added, updated, removed := principals(diff.Added), principals(diff.Updated), principals(diff.Removed)
+
+		if bulk {
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := []string{
+				"POST /sf/v3/Items(fo1)/AccessControls/BulkSet[u1 u4 u5] true",
+				"POST /sf/v3/Items(fo1)/AccessControls/BulkDelete[u3]",
+			}
+			if fmt.Sprint(calls) != fmt.Sprint(want) {
+				t.Errorf("bulk calls are %q", calls)
+			}
+			if fmt.Sprint(added, updated, removed) != "[u4 u5] [u1] [u3]" {
+				t.Errorf("bulk diff added %v, updated %v, removed %v", added, updated, removed)
+			}
+			continue
+		}
+
+		if !errors.Is(err, ErrForbidden) || !errors.Is(diff.Failed["u5"], ErrForbidden) || len(diff.Failed) != 1 {
+			t.Errorf("fallback failed with %v, per principal %v", err, diff.Failed)
+		}
+		if len(calls) != 6 {
+			t.Errorf("fallback calls are %q", calls)
+		}
+		if fmt.Sprint(added, updated, removed) != "[u4] [u1] [u3]" {
+			t.Errorf("fallback diff added %v, updated %v, removed %v", added, updated, removed)
+		}
+	}
+}
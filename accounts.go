@@ -0,0 +1,175 @@
+package go-sharefile
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// Account is the ShareFile account the package is signed in to. Features holds the account's feature toggles, every
+// true or false property the API reports, keyed by property name, so toggles added to ShareFile can be read without
+// a new field.
+type Account struct {
+	ID          string   `json:"Id"`
+	CompanyName string   `json:"CompanyName"`
+	PlanName    string   `json:"PlanName"`
+	AccountType string   `json:"AccountType"`
+	Subdomain   string   `json:"Subdomain"`
+	Subdomains  []string `json:"Subdomains"`
+
+	Features map[string]bool `json:"-"`
+}
+
+// UnmarshalJSON decodes the known fields and collects the boolean properties into Features.
+func (a *Account) UnmarshalJSON(b []byte) error {
+	type plain Account
+	if err := json.Unmarshal(b, (*plain)(a)); err != nil {
+		return err
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	a.Features = map[string]bool{}
+	for k, v := range raw {
+		if on, ok := v.(bool); ok {
+			a.Features[k] = on
+		}
+	}
+
+	return nil
+}
+
+// AccountInfo returns the account the package is signed in to.
+func AccountInfo(ctx context.Context) (*Account, error) {
+	var account Account
+	if err := call(ctx, "GET", "/sf/v3/Accounts", nil, &account); err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+// Usage is how much of the account's storage, bandwidth and licenses is in use. Storage and bandwidth are in bytes;
+// a zero limit means the plan sets none.
+type Usage struct {
+	StorageUsed    int64 `json:"DiskSpaceUsed"`
+	StorageQuota   int64 `json:"DiskSpaceLimit"`
+	BandwidthUsed  int64 `json:"BandwidthUsed"`
+	BandwidthQuota int64 `json:"BandwidthLimit"`
+	Employees      int   `json:"EmployeeCount"`
+	EmployeeLimit  int   `json:"EmployeeMax"`
+	Clients        int   `json:"ClientCount"`
+	ClientLimit    int   `json:"ClientMax"`
+}
+
+// AccountUsage returns the account's storage, bandwidth and license usage. It takes a single request selecting only
+// the usage figures, so it is cheap enough to poll.
+func AccountUsage(ctx context.Context) (*Usage, error) {
+	q := NewQuery().Select("DiskSpaceUsed", "DiskSpaceLimit", "BandwidthUsed", "BandwidthLimit",
+		"EmployeeCount", "EmployeeMax", "ClientCount", "ClientMax")
+
+	var usage Usage
+	if err := call(ctx, "GET", withQuery("/sf/v3/Accounts", q), nil, &usage); err != nil {
+		return nil, err
+	}
+
+	return &usage, nil
+}
+
+// AccountPreferences are the account-wide policy settings. ShareExpirationDays is the expiration of new shares, with
+// zero meaning they don't expire, and AllowedEmailDomains the domains shares may be sent to, empty when any is
+// allowed. Raw holds the full response, so settings without a field can still be read.
+type AccountPreferences struct {
+	RequireLoginByDefault bool     `json:"RequireLoginByDefault"`
+	ShareExpirationDays   int      `json:"ShareExpirationDays"`
+	NotifyDownload        bool     `json:"DefaultDownloadNotify"`
+	NotifyUpload          bool     `json:"DefaultUploadNotify"`
+	AllowedEmailDomains   []string `json:"AllowedEmailDomains"`
+	EnableViewOnly        bool     `json:"EnableViewOnly"`
+	PasswordMinLength     int      `json:"PasswordMinLength"`
+
+	Raw map[string]interface{} `json:"-"`
+}
+
+// UnmarshalJSON decodes the known preferences and keeps the full response in Raw.
+func (p *AccountPreferences) UnmarshalJSON(b []byte) error {
+	type plain AccountPreferences
+	if err := json.Unmarshal(b, (*plain)(p)); err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, &p.Raw)
+}
+
+// AccountPreferencesUpdate holds the preferences UpdateAccountPreferences changes. Nil fields are left as they are.
+type AccountPreferencesUpdate struct {
+	ShareExpirationDays *int  `json:",omitempty"`
+	NotifyDownload      *bool `json:"DefaultDownloadNotify,omitempty"`
+	NotifyUpload        *bool `json:"DefaultUploadNotify,omitempty"`
+}
+
+// GetAccountPreferences returns the policy settings of the account.
+func GetAccountPreferences(ctx context.Context) (*AccountPreferences, error) {
+	var prefs AccountPreferences
+	if err := call(ctx, "GET", "/sf/v3/Accounts/Preferences", nil, &prefs); err != nil {
+		return nil, err
+	}
+
+	return &prefs, nil
+}
+
+// UpdateAccountPreferences changes some of the policy settings of the account. Requires administrator rights.
+func UpdateAccountPreferences(ctx context.Context, update AccountPreferencesUpdate) error {
+	return call(ctx, "PATCH", "/sf/v3/Accounts/Preferences", update, nil)
+}
+
+// ErrWebAuthRequired is returned when signing in with a password is refused because the account only allows single
+// sign-on through its identity provider, which needs a browser.
+var ErrWebAuthRequired = errors.New("sharefile: account requires web authentication")
+
+// SSOInfo is the SAML single sign-on configuration of an account. EntityID and LoginURL identify the identity
+// provider. ForceSSO is set when users must sign in through it, leaving no password login.
+type SSOInfo struct {
+	SAMLEnabled bool   `json:"-"`
+	EntityID    string `json:"EntityID"`
+	LoginURL    string `json:"LoginUrl"`
+	LogoutURL   string `json:"LogoutUrl"`
+	ForceSSO    bool   `json:"ForceSSO"`
+}
+
+// PasswordLoginAllowed reports whether users of the account can still sign in with a password.
+func (i *SSOInfo) PasswordLoginAllowed() bool {
+	return !i.SAMLEnabled || !i.ForceSSO
+}
+
+// GetSSOInfo returns the single sign-on configuration of the account with the given subdomain. It needs no
+// authentication, so it can be called before signing in; an account without SAML comes back with SAMLEnabled unset.
+func GetSSOInfo(ctx context.Context, subdomain string) (*SSOInfo, error) {
+	params := url.Values{}
+	params.Set("provider", "saml")
+
+	u := fmt.Sprintf("https://%s.sf-api.com/sf/v3/Accounts/SSO?%s", url.PathEscape(subdomain), params.Encode())
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	var info SSOInfo
+	err = do(req, &info)
+	if errors.Is(err, ErrNotFound) || errors.Is(err, io.EOF) {
+		return &SSOInfo{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	info.SAMLEnabled = info.LoginURL != ""
+
+	return &info, nil
+}
@@ -0,0 +1,40 @@
+package go-sharefile
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestAccountUsageLargeValues(t *testing.T) {
+	fakeAPI(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sf/v3/Accounts" {
+			http.NotFound(w, r)
+			return
+		}
+		// 5 TiB used of 16 TiB, and bandwidth past what a float64 holds exactly.
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"DiskSpaceUsed":5497558138880,"DiskSpaceLimit":17592186044416,` +
+			`"BandwidthUsed":9007199254740993,"BandwidthLimit":4294967297,` +
+			`"EmployeeCount":12,"EmployeeMax":25,"ClientCount":340,"ClientMax":-1}`))
+	}))
+
+	usage, err := AccountUsage(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Usage{
+		StorageUsed:    5497558138880,
+		StorageQuota:   17592186044416,
+		BandwidthUsed:  9007199254740993,
+		BandwidthQuota: 1<<32 + 1,
+		Employees:      12,
+		EmployeeLimit:  25,
+		Clients:        340,
+		ClientLimit:    -1,
+	}
+	if *usage != want {
+		t.Errorf("AccountUsage = %+v, want %+v", *usage, want)
+	}
+}
@@ -0,0 +1,211 @@
+package go-sharefile
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Errors the API responses are mapped onto by status code, so callers can test for them with errors.Is.
+var (
+	ErrUnauthorized = errors.New("sharefile: unauthorized")
+	ErrForbidden    = errors.New("sharefile: forbidden")
+	ErrNotFound     = errors.New("sharefile: not found")
+	ErrConflict     = errors.New("sharefile: conflict")
+)
+
+// Logger receives the warnings the package reports when it carries on after a problem instead of failing. Replace it
+// to redirect or silence them.
+var Logger = log.New(os.Stderr, "sharefile: ", log.LstdFlags)
+
+// httpClient is shared by every request the package makes.
+var httpClient = &http.Client{CheckRedirect: checkRedirect}
+
+// APIError is returned for any response outside the 2xx range. Code and Message are taken from the ShareFile error
+// body when one is present.
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+	// RetryAfter is how long the API asked to wait before trying again, if it did.
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("sharefile: %d %s: %s", e.StatusCode, e.Code, e.Message)
+	}
+	return fmt.Sprintf("sharefile: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Unwrap returns the sentinel error matching the status code, if there is one.
+func (e *APIError) Unwrap() error {
+	switch e.StatusCode {
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusForbidden:
+		return ErrForbidden
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusConflict:
+		return ErrConflict
+	}
+	return nil
+}
+
+// Error body returned by the API, internal package use.
+type errorBody struct {
+	Code    string `json:"code"`
+	Message struct {
+		Value string `json:"value"`
+	} `json:"message"`
+}
+
+// Returns the full API URL for a path under the account's API host, internal package use.
+func apiURL(uriPath string) string {
+	return fmt.Sprintf("https://%s%s", getHostname(), uriPath)
+}
+
+// Builds an authorized request against the API, encoding body as JSON when it is not nil, internal package use.
+func newRequest(ctx context.Context, method, uriPath string, body interface{}) (*http.Request, error) {
+	var r io.Reader
+	if body != nil {
+		// HTML escaping is off so markup in names and contents reaches the API exactly as given.
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(body); err != nil {
+			return nil, err
+		}
+		r = &buf
+	}
+
+	req, err := http.NewRequest(method, apiURL(uriPath), r)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	req.Header.Add("Authorization", getAuthorizationHeader())
+	if body != nil {
+		req.Header.Add("Content-Type", "application/json")
+	}
+
+	return req, nil
+}
+
+// Sends a request and decodes the JSON response into out when it is not nil, internal package use.
+func do(req *http.Request, out interface{}) error {
+	resp, err := send(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := checkResponse(resp); err != nil {
+		return err
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// Most attempts made at a request the API keeps throttling, internal package use.
+const maxAttempts = 4
+
+// Sends a request, waiting and retrying while the API answers 429 Too Many Requests or 503 Service Unavailable. The
+// wait honours Retry-After and otherwise backs off exponentially from one second, internal package use.
+func send(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable
+		if !retryable || attempt == maxAttempts || (req.Body != nil && req.GetBody == nil) {
+			return resp, nil
+		}
+
+		delay := retryDelay(resp, attempt)
+		resp.Body.Close()
+
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+
+		t := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			t.Stop()
+			return nil, req.Context().Err()
+		case <-t.C:
+		}
+	}
+}
+
+// Returns how long to wait before retrying a throttled request, or one that failed without a response when resp is
+// nil, internal package use.
+func retryDelay(resp *http.Response, attempt int) time.Duration {
+	if resp == nil {
+		return time.Second << uint(attempt-1)
+	}
+	if v := resp.Header.Get("Retry-After"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+			return time.Duration(secs) * time.Second
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			if d := time.Until(t); d > 0 {
+				return d
+			}
+			return 0
+		}
+	}
+
+	return time.Second << uint(attempt-1)
+}
+
+// Builds and sends an API request in one step, internal package use.
+func call(ctx context.Context, method, uriPath string, body, out interface{}) error {
+	req, err := newRequest(ctx, method, uriPath, body)
+	if err != nil {
+		return err
+	}
+
+	return do(req, out)
+}
+
+// Returns an *APIError for responses outside the 2xx range, internal package use.
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+	if resp.Header.Get("Retry-After") != "" {
+		apiErr.RetryAfter = retryDelay(resp, 1)
+	}
+
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	var e errorBody
+	if json.Unmarshal(body, &e) == nil {
+		apiErr.Code = e.Code
+		apiErr.Message = e.Message.Value
+	}
+
+	return apiErr
+}
@@ -0,0 +1,75 @@
+package go-sharefile
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// Sends every request to a test server, chosen by the host the package addressed, which the server still sees as the
+// request's Host.
+type fakeTransport struct {
+	hosts    map[string]*url.URL
+	fallback *url.URL
+}
+
+func (t *fakeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	target := t.fallback
+	if u, ok := t.hosts[req.URL.Host]; ok {
+		target = u
+	}
+
+	r := req.Clone(req.Context())
+	r.URL.Scheme = target.Scheme
+	r.URL.Host = target.Host
+	r.Host = req.URL.Host
+
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// Starts a test server standing in for the account's API host and any other host not given to fakeHost, and signs
+// the package in to it for the duration of the test.
+func fakeAPI(t testing.TB, handler http.Handler) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	target, _ := url.Parse(srv.URL)
+
+	savedToken, savedTransport := token, httpClient.Transport
+	token = map[string]string{"access_token": "test-token", "subdomain": "acme"}
+	httpClient.Transport = &fakeTransport{hosts: map[string]*url.URL{}, fallback: target}
+	resetCapabilities()
+
+	t.Cleanup(func() {
+		srv.Close()
+		token, httpClient.Transport = savedToken, savedTransport
+		resetCapabilities()
+	})
+
+	return srv
+}
+
+// Starts a second test server answering for host, which must be called after fakeAPI.
+func fakeHost(t testing.TB, host string, handler http.Handler) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	target, _ := url.Parse(srv.URL)
+	httpClient.Transport.(*fakeTransport).hosts[host] = target
+
+	return srv
+}
+
+// Writes v as a JSON response.
+func writeJSON(t testing.TB, w http.ResponseWriter, v interface{}) {
+	t.Helper()
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		t.Errorf("encoding response: %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package go-sharefile
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// UploadBatch uploads a set of files that belong together into one folder as a single ShareFile batch, so the folder's
+// subscribers get one notification for the whole set and the web interface shows the files as a group.
+type UploadBatch struct {
+	folderID string
+	paths    []string
+	opts     []UploadOption
+}
+
+// NewUploadBatch starts an empty batch of uploads to a folder. The options apply to every file of the batch;
+// WithConcurrency sets how many files are uploaded at once.
+func NewUploadBatch(folderID string, opts ...UploadOption) *UploadBatch {
+	return &UploadBatch{
+		folderID: folderID,
+		opts:     opts,
+	}
+}
+
+// Add queues a local file for upload with the batch.
+func (b *UploadBatch) Add(localPath string) {
+	b.paths = append(b.paths, localPath)
+}
+
+// Run uploads the queued files and returns a result for every file in the order they were added, as UploadFiles does.
+// The files are uploaded concurrently except the last one, which closes the batch and is only started once all the
+// others have finished, whether or not they succeeded. The returned error joins the failures.
+func (b *UploadBatch) Run(ctx context.Context) ([]UploadResult, error) {
+	if len(b.paths) == 0 {
+		return nil, nil
+	}
+
+	id, err := newBatchID()
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(b.paths) - 1
+	opts := append(b.opts[:len(b.opts):len(b.opts)], withBatch(id, false))
+	results, _ := UploadFiles(ctx, b.folderID, b.paths[:n], newUploadOptions(b.opts).workers(), opts...)
+
+	last := UploadResult{Path: b.paths[n]}
+	if ctx.Err() != nil {
+		last.Err = ctx.Err()
+	} else {
+		opts[len(opts)-1] = withBatch(id, true)
+		start := time.Now()
+		last.Item, last.Err = UploadFile(ctx, last.Path, b.folderID, opts...)
+		last.Duration = time.Since(start)
+		if last.Item != nil {
+			last.Hash = last.Item.Hash
+		}
+	}
+	results = append(results, last)
+
+	var errs []error
+	for _, r := range results {
+		if r.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", r.Path, r.Err))
+		}
+	}
+
+	return results, errors.Join(errs...)
+}
+
+// Marks an upload as part of a batch, and as the one closing it when last is true, internal package use.
+func withBatch(id string, last bool) UploadOption {
+	return func(o *uploadOptions) {
+		o.batchID = id
+		o.batchLast = last
+	}
+}
+
+// Returns a new random batch ID in the GUID form ShareFile uses for IDs, internal package use.
+func newBatchID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
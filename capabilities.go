@@ -0,0 +1,150 @@
+package go-sharefile
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Capabilities known to the package.
+const (
+	// CapabilityMaxUploadSize holds the largest file, in bytes, the account's storage accepts.
+	CapabilityMaxUploadSize = "MaxUploadSize"
+)
+
+// ErrUploadTooLarge is returned when a file is larger than the account's MaxUploadSize capability allows.
+var ErrUploadTooLarge = errors.New("sharefile: file exceeds the maximum upload size")
+
+// Capability is a feature the account's plan and storage zones support. Value is only set for capabilities that carry
+// a setting, such as MaxUploadSize.
+type Capability struct {
+	Name  string          `json:"Name"`
+	Value json.RawMessage `json:"Value"`
+}
+
+// Int returns the capability's value as a number, whether the API sent it as one or as a string.
+func (c *Capability) Int() (int64, bool) {
+	if len(c.Value) == 0 {
+		return 0, false
+	}
+
+	var s string
+	if json.Unmarshal(c.Value, &s) != nil {
+		s = string(c.Value)
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	return n, err == nil
+}
+
+// Collection response wrapping a list of capabilities, internal package use.
+type capabilityFeed struct {
+	Capabilities []Capability `json:"value"`
+}
+
+// Capabilities returns the features the account supports.
+func Capabilities(ctx context.Context) ([]Capability, error) {
+	var feed capabilityFeed
+	if err := call(ctx, "GET", "/sf/v3/Capabilities", nil, &feed); err != nil {
+		return nil, err
+	}
+
+	return feed.Capabilities, nil
+}
+
+// How long a failure to fetch the capabilities is remembered before they are asked for again, internal package use.
+const capabilityRetryAfter = 30 * time.Second
+
+// Capabilities of the signed in account, fetched once and dropped when signing in again, internal package use. A
+// failed fetch is kept for capabilityRetryAfter so callers don't each wait on a failing request; generation counts the
+// resets, so a fetch that started before one isn't stored after it.
+var capabilityCache struct {
+	mu         sync.Mutex
+	byName     map[string]Capability
+	err        error
+	retryAt    time.Time
+	generation int
+}
+
+// Returns the account's capabilities by name, fetching them on first use, internal package use. The fetch runs
+// without holding the cache's lock, so a slow request doesn't hold up a reset.
+func cachedCapabilities(ctx context.Context) (map[string]Capability, error) {
+	capabilityCache.mu.Lock()
+	byName, err, generation := capabilityCache.byName, capabilityCache.err, capabilityCache.generation
+	if err != nil && time.Now().After(capabilityCache.retryAt) {
+		err = nil
+	}
+	capabilityCache.mu.Unlock()
+
+	if byName != nil || err != nil {
+		return byName, err
+	}
+
+	caps, err := Capabilities(ctx)
+	if err != nil && ctx.Err() != nil {
+		// The caller gave up; that says nothing about the account.
+		return nil, err
+	}
+	if err == nil {
+		byName = make(map[string]Capability, len(caps))
+		for _, c := range caps {
+			byName[c.Name] = c
+		}
+	}
+
+	capabilityCache.mu.Lock()
+	if capabilityCache.generation == generation {
+		capabilityCache.byName, capabilityCache.err = byName, err
+		capabilityCache.retryAt = time.Now().Add(capabilityRetryAfter)
+	}
+	capabilityCache.mu.Unlock()
+
+	return byName, err
+}
+
+// Forgets the cached capabilities, internal package use.
+func resetCapabilities() {
+	capabilityCache.mu.Lock()
+	capabilityCache.byName, capabilityCache.err = nil, nil
+	capabilityCache.generation++
+	capabilityCache.mu.Unlock()
+}
+
+// HasCapability reports whether the account supports the named capability. The list is fetched on first use and kept
+// until the next Login; when it can't be fetched, HasCapability reports false, and the list isn't asked for again for
+// the next 30 seconds.
+func HasCapability(name string) bool {
+	caps, err := cachedCapabilities(context.Background())
+	if err != nil {
+		return false
+	}
+
+	_, ok := caps[name]
+	return ok
+}
+
+// Refuses a file larger than the account's MaxUploadSize capability, internal package use. Uploads of unknown size,
+// and any made while the capabilities can't be fetched, are let through for the API to judge.
+func checkUploadSize(ctx context.Context, name string, size int64) error {
+	if size < 0 {
+		return nil
+	}
+
+	caps, err := cachedCapabilities(ctx)
+	if err != nil {
+		return nil
+	}
+	c, ok := caps[CapabilityMaxUploadSize]
+	if !ok {
+		return nil
+	}
+	limit, ok := c.Int()
+	if !ok || limit <= 0 || size <= limit {
+		return nil
+	}
+
+	return fmt.Errorf("sharefile: %q is %d bytes, over the account's limit of %d: %w", name, size, limit, ErrUploadTooLarge)
+}
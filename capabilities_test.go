@@ -0,0 +1,97 @@
+package go-sharefile
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCapabilitiesCached(t *testing.T) {
+	var fetches int32
+	fakeAPI(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		writeJSON(t, w, map[string]interface{}{"value": []map[string]interface{}{
+			{"Name": CapabilityMaxUploadSize, "Value": "1000"},
+		}})
+	}))
+
+	ctx := context.Background()
+	if err := checkUploadSize(ctx, "a.bin", 1000); err != nil {
+		t.Errorf("file at the limit refused: %v", err)
+	}
+	if err := checkUploadSize(ctx, "b.bin", 1001); !errors.Is(err, ErrUploadTooLarge) {
+		t.Errorf("file over the limit returned %v, want ErrUploadTooLarge", err)
+	}
+	if !HasCapability(CapabilityMaxUploadSize) {
+		t.Error("HasCapability reports no MaxUploadSize")
+	}
+	if n := atomic.LoadInt32(&fetches); n != 1 {
+		t.Errorf("capabilities fetched %d times, want once", n)
+	}
+}
+
+func TestCapabilitiesFailureCached(t *testing.T) {
+	var fetches int32
+	fakeAPI(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+
+	for i := 0; i < 3; i++ {
+		if HasCapability(CapabilityMaxUploadSize) {
+			t.Error("HasCapability reports true without capabilities")
+		}
+		if err := checkUploadSize(context.Background(), "a.bin", 1<<40); err != nil {
+			t.Errorf("upload refused without capabilities: %v", err)
+		}
+	}
+	if n := atomic.LoadInt32(&fetches); n != 1 {
+		t.Errorf("failing capabilities fetched %d times, want once", n)
+	}
+
+	// Once the failure is old enough, the capabilities are asked for again.
+	capabilityCache.mu.Lock()
+	capabilityCache.retryAt = time.Now().Add(-time.Second)
+	capabilityCache.mu.Unlock()
+	HasCapability(CapabilityMaxUploadSize)
+	if n := atomic.LoadInt32(&fetches); n != 2 {
+		t.Errorf("capabilities fetched %d times after the failure expired, want twice", n)
+	}
+}
+
+func TestCapabilitiesFetchUnlocked(t *testing.T) {
+	release := make(chan struct{})
+	fakeAPI(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		writeJSON(t, w, map[string]interface{}{"value": []map[string]string{{"Name": "Stale"}}})
+	}))
+
+	done := make(chan bool)
+	go func() { done <- HasCapability("Stale") }()
+
+	// A reset, as Login does, mustn't wait for the fetch in progress, nor have it stored afterwards.
+	time.Sleep(50 * time.Millisecond)
+	reset := make(chan struct{})
+	go func() {
+		resetCapabilities()
+		close(reset)
+	}()
+	select {
+	case <-reset:
+	case <-time.After(time.Second):
+		close(release)
+		t.Fatal("resetCapabilities waited for the capabilities request")
+	}
+
+	close(release)
+	<-done
+	capabilityCache.mu.Lock()
+	stored := capabilityCache.byName
+	capabilityCache.mu.Unlock()
+	if stored != nil {
+		t.Errorf("capabilities fetched before the reset were kept: %v", stored)
+	}
+}
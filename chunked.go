@@ -0,0 +1,243 @@
+package go-sharefile
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Defaults for threaded uploads, internal package use.
+const (
+	defaultChunkSize      = 8 << 20
+	defaultParallelChunks = 4
+	threadedThreshold     = 64 << 20
+	maxChunkAttempts      = 3
+)
+
+// WithChunkSize uploads the file with the threaded method in chunks of the given number of bytes. The default chunk
+// size is 8 MB.
+func WithChunkSize(bytes int64) UploadOption {
+	return func(o *uploadOptions) {
+		o.chunkSize = bytes
+	}
+}
+
+// WithParallelChunks uploads the file with the threaded method, sending up to n chunks at once. The default is 4.
+func WithParallelChunks(n int) UploadOption {
+	return func(o *uploadOptions) {
+		o.parallel = n
+	}
+}
+
+// Reports whether an upload of the given size uses the threaded method, internal package use.
+func (o *uploadOptions) useThreaded(size int64) bool {
+	return o.chunkSize > 0 || o.parallel > 0 || size >= threadedThreshold
+}
+
+// Returns the chunk size of threaded uploads, internal package use.
+func (o *uploadOptions) chunkBytes() int64 {
+	if o.chunkSize <= 0 {
+		return defaultChunkSize
+	}
+	return o.chunkSize
+}
+
+// Returns the number of chunks sent at once by threaded uploads, internal package use.
+func (o *uploadOptions) parallelChunks() int {
+	if o.parallel <= 0 {
+		return defaultParallelChunks
+	}
+	return o.parallel
+}
+
+// Uploads r in chunks to the spec's ChunkUri and completes the upload at its FinishUri, returning the finish
+// response, internal package use. Chunks are read one after another, so the whole file is hashed in a single pass,
+// and sent by a bounded pool of workers.
+//
+// When the spec expires part way, a fresh one is requested through renew and the chunks not yet confirmed are sent to
+// it.
+func threadedUpload(ctx context.Context, spec *uploadSpec, renew func() (*uploadSpec, error), r io.Reader, size int64, o *uploadOptions) ([]byte, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	fail := func(err error) {
+		mu.Lock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+		mu.Unlock()
+	}
+
+	specs := &chunkSpec{spec: spec, renew: renew}
+	sem := make(chan struct{}, o.parallelChunks())
+	fileHash := md5.New()
+	var offset int64
+
+	for index := 0; ; index++ {
+		buf := make([]byte, o.chunkBytes())
+		n, err := io.ReadFull(r, buf)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			fail(err)
+			break
+		}
+		if n == 0 {
+			break
+		}
+		chunk := buf[:n]
+		fileHash.Write(chunk)
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(index int, offset int64, chunk []byte) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			for {
+				spec := specs.current()
+				err := postChunk(ctx, spec.ChunkURI, index, offset, chunk)
+				if err == nil {
+					return
+				}
+				if !uploadSpecExpired(err) {
+					fail(err)
+					return
+				}
+				if _, err := specs.replace(spec); err != nil {
+					fail(err)
+					return
+				}
+			}
+		}(index, offset, chunk)
+
+		offset += int64(n)
+		if n < len(buf) {
+			break
+		}
+	}
+
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if size >= 0 && offset != size {
+		return nil, fmt.Errorf("sharefile: read %d bytes for an upload of %d", offset, size)
+	}
+
+	finishURL := fmt.Sprintf("%s&fileSize=%d&fileHash=%s", specs.current().FinishURI, offset, hex.EncodeToString(fileHash.Sum(nil)))
+	req, err := http.NewRequest("POST", finishURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	resp, err := send(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+// Upload specification shared by the chunks of a threaded upload, renewed when it expires, internal package use.
+type chunkSpec struct {
+	mu       sync.Mutex
+	spec     *uploadSpec
+	renew    func() (*uploadSpec, error)
+	renewals int
+}
+
+// Returns the spec chunks are currently sent to, internal package use.
+func (s *chunkSpec) current() *uploadSpec {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.spec
+}
+
+// Replaces an expired spec with a fresh one, unless another chunk already has, internal package use.
+func (s *chunkSpec) replace(expired *uploadSpec) (*uploadSpec, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.spec != expired {
+		return s.spec, nil
+	}
+	if s.renewals >= maxSpecRenewals {
+		return nil, fmt.Errorf("sharefile: upload URL expired %d times", s.renewals+1)
+	}
+	s.renewals++
+	Logger.Printf("upload URL expired, requesting a new one")
+
+	spec, err := s.renew()
+	if err != nil {
+		return nil, err
+	}
+	s.spec = spec
+	return spec, nil
+}
+
+// Sends one chunk of a threaded upload, retrying it on failure, internal package use.
+func postChunk(ctx context.Context, chunkURI string, index int, offset int64, chunk []byte) error {
+	sum := md5.Sum(chunk)
+	chunkURL := fmt.Sprintf("%s&index=%d&byteOffset=%d&hash=%s", chunkURI, index, offset, hex.EncodeToString(sum[:]))
+
+	var err error
+	for attempt := 1; attempt <= maxChunkAttempts; attempt++ {
+		if attempt > 1 {
+			t := time.NewTimer(time.Second << uint(attempt-2))
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return ctx.Err()
+			case <-t.C:
+			}
+		}
+
+		var req *http.Request
+		req, err = http.NewRequest("POST", chunkURL, bytes.NewReader(chunk))
+		if err != nil {
+			return err
+		}
+		req = req.WithContext(ctx)
+		req.Header.Set("Content-Type", "application/octet-stream")
+
+		var resp *http.Response
+		resp, err = send(req)
+		if err == nil {
+			err = checkResponse(resp)
+			resp.Body.Close()
+		}
+		// An expired spec won't come back; the caller needs a new one.
+		if err == nil || ctx.Err() != nil || uploadSpecExpired(err) {
+			return err
+		}
+	}
+
+	return fmt.Errorf("sharefile: chunk %d failed after %d attempts: %w", index, maxChunkAttempts, err)
+}
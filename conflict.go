@@ -0,0 +1,24 @@
+package go-sharefile
+
+import "fmt"
+
+// ConflictPolicy selects what happens when the destination already has an item with the same name.
+type ConflictPolicy int
+
+// Conflict policies for creates, uploads, copies and moves.
+const (
+	// ConflictFail fails with an error wrapping ErrConflict.
+	ConflictFail ConflictPolicy = iota
+	// ConflictOverwrite replaces the existing item.
+	ConflictOverwrite
+	// ConflictRename keeps the existing item and stores the new one under a numbered name, such as "report (2)".
+	ConflictRename
+)
+
+// Most numbered names tried by ConflictRename before giving up, internal package use.
+const maxRenameAttempts = 100
+
+// Returns the numbered variant of a name used by ConflictRename, internal package use.
+func numberedName(name string, n int) string {
+	return fmt.Sprintf("%s (%d)", name, n)
+}
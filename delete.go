@@ -0,0 +1,97 @@
+package go-sharefile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Number of items deleted per BulkDelete request when DeleteOptions doesn't set one.
+const defaultDeleteBatchSize = 100
+
+// DeleteOptions configures EmptyFolder.
+type DeleteOptions struct {
+	// DryRun counts the children that would be deleted without deleting anything.
+	DryRun bool
+	// Permanent deletes the children outright instead of moving them to the recycle bin.
+	Permanent bool
+	// BatchSize is the number of children deleted per request. Zero uses a default of 100.
+	BatchSize int
+}
+
+// Struct for use in bulk delete POST activities
+type bulkDeleteBody struct {
+	IDs []string `json:"ids"`
+}
+
+// EmptyFolder deletes every child of a folder, keeping the folder itself along with its permissions and share links,
+// and returns the number of children deleted. Children the caller isn't allowed to delete are skipped and logged
+// rather than stopping the purge. Each batch is deleted before the call moves on, so the count is what is gone by the
+// time it returns, and a large folder takes as long as the server needs to delete it.
+func EmptyFolder(ctx context.Context, folderID string, opts DeleteOptions) (int, error) {
+	// Collect the IDs up front: deleting while paging would shift the pages under the iterator.
+	var ids []string
+	it := NewChildIterator(ctx, folderID, ListOptions{Query: NewQuery().Select("Id")})
+	for it.Next() {
+		ids = append(ids, it.Item().ID)
+	}
+	if err := it.Err(); err != nil {
+		return 0, err
+	}
+
+	if opts.DryRun {
+		return len(ids), nil
+	}
+
+	size := opts.BatchSize
+	if size <= 0 {
+		size = defaultDeleteBatchSize
+	}
+
+	deleted := 0
+	for start := 0; start < len(ids); start += size {
+		end := start + size
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		n, err := deleteBatch(ctx, ids[start:end], opts.Permanent)
+		deleted += n
+		if err != nil {
+			return deleted, err
+		}
+	}
+
+	return deleted, nil
+}
+
+// Deletes a batch of items, falling back to one at a time when the batch is refused so the items the caller may
+// delete still go, internal package use.
+func deleteBatch(ctx context.Context, ids []string, permanent bool) (int, error) {
+	// Without forceSync the server answers with an operation before deleting anything, and refusals would only show
+	// once it fails; a synchronous delete reports them here, where the batch can still be retried one item at a time.
+	uriPath := fmt.Sprintf("/sf/v3/Items/BulkDelete?forceSync=true&deletePermanently=%t", permanent)
+	err := call(ctx, "POST", uriPath, bulkDeleteBody{IDs: ids}, nil)
+	if err == nil {
+		return len(ids), nil
+	}
+	if !errors.Is(err, ErrForbidden) {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, id := range ids {
+		uriPath := fmt.Sprintf("/sf/v3/Items(%s)?deletePermanently=%t", id, permanent)
+		err := call(ctx, "DELETE", uriPath, nil, nil)
+		if errors.Is(err, ErrForbidden) {
+			Logger.Printf("skipping item %s: not allowed to delete it", id)
+			continue
+		}
+		if err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+
+	return deleted, nil
+}
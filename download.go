@@ -0,0 +1,429 @@
+package go-sharefile
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// ProgressFunc is called as a transfer advances, with the number of bytes transferred so far and the total size, or
+// -1 when the size isn't known.
+type ProgressFunc func(done, total int64)
+
+// DownloadOption configures a download.
+type DownloadOption func(*downloadOptions)
+
+// Options collected from DownloadOption values, internal package use.
+type downloadOptions struct {
+	progress    ProgressFunc
+	parts       int
+	verify      bool
+	allVersions bool
+	skipCurrent bool
+	pdf         bool
+}
+
+// WithDownloadProgress reports the progress of a download to fn.
+func WithDownloadProgress(fn ProgressFunc) DownloadOption {
+	return func(o *downloadOptions) {
+		o.progress = fn
+	}
+}
+
+// WithParallelDownload makes DownloadItem fetch files of 64 MB and over as n byte ranges at once, each written straight
+// to its place in the local file. An n of 0 or less uses 4. The download falls back to a single stream when the host
+// doesn't honor range requests.
+func WithParallelDownload(n int) DownloadOption {
+	return func(o *downloadOptions) {
+		if n <= 0 {
+			n = defaultDownloadParts
+		}
+		o.parts = n
+	}
+}
+
+// WithVerifyChecksum computes the MD5 hash of a file as it is downloaded and compares it with the hash ShareFile
+// stored, failing with an error wrapping ErrChecksumMismatch when they differ. DownloadItem removes the local file on
+// a mismatch. Folders, downloaded as zip archives, have no stored hash and aren't verified.
+func WithVerifyChecksum() DownloadOption {
+	return func(o *downloadOptions) {
+		o.verify = true
+	}
+}
+
+// WithAllVersions asks for a zip archive holding every stored version of a file rather than its current content. It
+// can't be used on folders, and the archive isn't verified by WithVerifyChecksum. Use Versions and download the
+// versions one by one to get them as separate files.
+func WithAllVersions() DownloadOption {
+	return func(o *downloadOptions) {
+		o.allVersions = true
+	}
+}
+
+// ErrNotConvertible is returned when an item was asked for in a rendition, such as with WithWatermarkedPDF, that its
+// type can't be converted to.
+var ErrNotConvertible = errors.New("sharefile: item can't be converted")
+
+// WithWatermarkedPDF downloads a document as the PDF rendition ShareFile produces for viewing, carrying the watermark
+// the account applies to protected documents, instead of its original bytes. Items that can't be converted fail with
+// an error wrapping ErrNotConvertible. The rendition isn't verified by WithVerifyChecksum.
+func WithWatermarkedPDF() DownloadOption {
+	return func(o *downloadOptions) {
+		o.pdf = true
+	}
+}
+
+// WithSkipIfCurrent makes DownloadItem leave the local file alone when it already has the size and MD5 hash of the
+// item, reporting the download as skipped. The local file is only hashed when the sizes match. Items without a stored
+// hash count as current when the local file was modified after the item last changed.
+func WithSkipIfCurrent() DownloadOption {
+	return func(o *downloadOptions) {
+		o.skipCurrent = true
+	}
+}
+
+// DownloadResult is the outcome of a download.
+type DownloadResult struct {
+	// Bytes is the number of bytes written.
+	Bytes int64
+	// Hash is the MD5 hash of the downloaded content, set when WithVerifyChecksum is given.
+	Hash string
+	// AllVersions reports that the content is a zip archive of every version of the file, from WithAllVersions,
+	// rather than the file itself.
+	AllVersions bool
+	// ContentType is the content type the download was served with.
+	ContentType string
+	// Skipped reports that the local file was already current and nothing was downloaded, from WithSkipIfCurrent.
+	Skipped bool
+}
+
+// Applies download options over the defaults, internal package use.
+func newDownloadOptions(opts []DownloadOption) *downloadOptions {
+	o := &downloadOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Wraps w so writes are reported to the progress callback, if one is set, internal package use.
+func (o *downloadOptions) progressWriter(w io.Writer, total int64) io.Writer {
+	if o.progress == nil {
+		return w
+	}
+	return &progressWriter{w: w, total: total, fn: o.progress}
+}
+
+// Writer reporting the running byte count to a ProgressFunc, internal package use.
+type progressWriter struct {
+	w     io.Writer
+	done  int64
+	total int64
+	fn    ProgressFunc
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.done += int64(n)
+	p.fn(p.done, p.total)
+	return n, err
+}
+
+// How long a download URL is assumed to stay valid when the API doesn't say, internal package use.
+const downloadURLLifetime = 5 * time.Minute
+
+// DownloadSpec is a short-lived URL from which an item can be downloaded without further authorization, such as by a
+// browser.
+type DownloadSpec struct {
+	URL   string `json:"DownloadUrl"`
+	Token string `json:"DownloadToken"`
+	// Expires is when the URL stops working. ShareFile doesn't always report it, in which case it is set conservatively
+	// a few minutes ahead; don't hand out or cache the URL beyond it.
+	Expires time.Time `json:"ExpirationDate"`
+}
+
+// DownloadURL returns a URL for downloading an item without downloading it. Items in storage zones get a URL on the
+// zone's host. WithAllVersions and WithWatermarkedPDF are the only download options that apply.
+func DownloadURL(ctx context.Context, itemID string, opts ...DownloadOption) (*DownloadSpec, error) {
+	o := newDownloadOptions(opts)
+
+	specURL, err := itemResourceURL(ctx, itemID, "/Download")
+	if err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("redirect", "false")
+	if o.allVersions {
+		params.Set("includeAllVersions", "true")
+	}
+	if o.pdf {
+		params.Set("format", "pdf")
+	}
+
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s?%s", specURL, params.Encode()), nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	req.Header.Add("Authorization", getAuthorizationHeader())
+
+	var spec DownloadSpec
+	if err := do(req, &spec); err != nil {
+		return nil, o.conversionError(itemID, err)
+	}
+	if spec.URL == "" {
+		return nil, fmt.Errorf("sharefile: no download URL received for item %s", itemID)
+	}
+	if spec.Expires.IsZero() {
+		spec.Expires = time.Now().Add(downloadURLLifetime)
+	}
+
+	return &spec, nil
+}
+
+// Download streams the contents of an item to w. Folders are downloaded as a zip archive. Nothing is written when the
+// API refuses the download.
+func Download(ctx context.Context, itemID string, w io.Writer, opts ...DownloadOption) (*DownloadResult, error) {
+	o := newDownloadOptions(opts)
+
+	var expected string
+	if o.verify || o.allVersions {
+		item, err := GetItemByID(ctx, itemID, NewQuery().Select("Id", "Hash"))
+		if err != nil {
+			return nil, err
+		}
+		if o.allVersions && item.IsFolder() {
+			return nil, fmt.Errorf("sharefile: item %s is a folder, which has no versions to download", itemID)
+		}
+		// Version archives and renditions don't match the file's stored hash.
+		if o.verify && !o.allVersions && !o.pdf {
+			expected = storedHash(item)
+		}
+	}
+
+	spec, err := DownloadURL(ctx, itemID, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := downloadRequest(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := send(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := checkResponse(resp); err != nil {
+		return nil, o.conversionError(itemID, err)
+	}
+
+	result, err := copyDownload(ctx, itemID, o.progressWriter(w, resp.ContentLength), resp.Body, expected, o)
+	if result != nil {
+		result.AllVersions = o.allVersions
+		result.ContentType = resp.Header.Get("Content-Type")
+	}
+
+	return result, err
+}
+
+// Reports whether a local file already holds the content of an item, internal package use.
+func localCurrent(localPath string, item *Item) (bool, error) {
+	info, err := os.Stat(localPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if item.IsFolder() || info.IsDir() || info.Size() != item.FileSizeBytes {
+		return false, nil
+	}
+
+	if item.Hash == "" {
+		return !item.ProgenyEditDate.IsZero() && info.ModTime().After(item.ProgenyEditDate), nil
+	}
+
+	f, err := os.Open(localPath)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	sum := md5.New()
+	if _, err := io.Copy(sum, f); err != nil {
+		return false, err
+	}
+
+	return strings.EqualFold(item.Hash, hex.EncodeToString(sum.Sum(nil))), nil
+}
+
+// Versions returns the stored versions of a file, newest first, each with its own ID that can be downloaded like any
+// other item.
+func Versions(ctx context.Context, itemID string) ([]Item, error) {
+	var feed itemFeed
+	if err := call(ctx, "GET", fmt.Sprintf("/sf/v3/Items(%s)/Versions", itemID), nil, &feed); err != nil {
+		return nil, err
+	}
+
+	return feed.Items, nil
+}
+
+// Reports a refused rendition as ErrNotConvertible, internal package use.
+func (o *downloadOptions) conversionError(itemID string, err error) error {
+	var apiErr *APIError
+	if !o.pdf || !errors.As(err, &apiErr) {
+		return err
+	}
+	switch apiErr.StatusCode {
+	case http.StatusBadRequest, http.StatusNotImplemented, http.StatusUnsupportedMediaType, http.StatusUnprocessableEntity:
+		return fmt.Errorf("sharefile: item %s as PDF (%v): %w", itemID, err, ErrNotConvertible)
+	}
+	return err
+}
+
+// Builds the GET request for a download URL, internal package use. The URL carries its own authorization, but the
+// account's is sent along to hosts that accept it.
+func downloadRequest(ctx context.Context, spec *DownloadSpec) (*http.Request, error) {
+	req, err := http.NewRequest("GET", spec.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	if isZoneHost(req.URL.Host) {
+		req.Header.Add("Authorization", getAuthorizationHeader())
+	}
+
+	return req, nil
+}
+
+// Returns the hash a download of an item is verified against, or an empty string when it has none, internal package
+// use.
+func storedHash(item *Item) string {
+	if item.IsFolder() || item.Hash == "" {
+		Logger.Printf("item %s has no stored hash, skipping checksum verification", item.ID)
+		return ""
+	}
+	return item.Hash
+}
+
+// Copies a download to w, hashing it on the way when verification is asked for, internal package use.
+func copyDownload(ctx context.Context, itemID string, w io.Writer, r io.Reader, expected string, o *downloadOptions) (*DownloadResult, error) {
+	var sum hash.Hash
+	if o.verify {
+		sum = md5.New()
+		w = io.MultiWriter(w, sum)
+	}
+
+	n, err := io.Copy(w, &ctxReader{ctx: ctx, r: r})
+	result := &DownloadResult{Bytes: n}
+	if err != nil {
+		return result, downloadError(ctx, itemID, err)
+	}
+	if sum == nil {
+		return result, nil
+	}
+
+	result.Hash = hex.EncodeToString(sum.Sum(nil))
+	return result, verifyDownload(itemID, expected, result.Hash)
+}
+
+// Reader that stops at the first read after its context is done, internal package use.
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *ctxReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
+
+// Tells a cancelled download apart from a failed one, wrapping the context's error in the former case, internal
+// package use.
+func downloadError(ctx context.Context, itemID string, err error) error {
+	if ctx.Err() != nil {
+		return fmt.Errorf("sharefile: download of item %s interrupted: %w", itemID, ctx.Err())
+	}
+	return err
+}
+
+// Compares the hash of a download with the stored one, internal package use.
+func verifyDownload(itemID, expected, local string) error {
+	if expected == "" || strings.EqualFold(expected, local) {
+		return nil
+	}
+	return fmt.Errorf("sharefile: item %s downloaded with MD5 %s, stored as %s: %w", itemID, local, expected, ErrChecksumMismatch)
+}
+
+// DownloadItems downloads several children of one folder as a single zip archive, streamed to w. Every item must
+// have parentID as its parent.
+func DownloadItems(ctx context.Context, parentID string, itemIDs []string, w io.Writer, opts ...DownloadOption) error {
+	if len(itemIDs) == 0 {
+		return fmt.Errorf("sharefile: no items to download")
+	}
+
+	o := newDownloadOptions(opts)
+
+	// One listing of the folder checks every item, rather than a request per item.
+	children := map[string]bool{}
+	it := NewChildIterator(ctx, parentID, ListOptions{Query: NewQuery().Select("Id")})
+	for it.Next() {
+		children[it.Item().ID] = true
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+	for _, id := range itemIDs {
+		if !children[id] {
+			return fmt.Errorf("sharefile: item %s is not a child of folder %s", id, parentID)
+		}
+	}
+
+	params := url.Values{}
+	for _, id := range itemIDs {
+		params.Add("ids", id)
+	}
+	params.Set("redirect", "true")
+	uriPath := fmt.Sprintf("/sf/v3/Items(%s)/BulkDownload?%s", parentID, params.Encode())
+
+	req, err := newRequest(ctx, "GET", uriPath, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := send(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := checkResponse(resp); err != nil {
+		switch resp.StatusCode {
+		case http.StatusBadRequest, http.StatusRequestEntityTooLarge, http.StatusRequestURITooLong:
+			return fmt.Errorf("sharefile: bulk download of %d items rejected, try fewer items per call: %w", len(itemIDs), err)
+		}
+		return err
+	}
+
+	_, err = io.Copy(o.progressWriter(w, resp.ContentLength), resp.Body)
+	return err
+}
@@ -0,0 +1,217 @@
+package go-sharefile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Fake of the account's API host serving one file, fi1, of zero bytes, with range requests. Each response is sent no
+// faster than rate bytes a second, when set, as a throttled connection would.
+type fakeDownload struct {
+	t    testing.TB
+	size int64
+	rate int64
+	// fail, when set, picks a status to answer a content request with instead of the content.
+	fail func(r *http.Request) int
+
+	requests int32
+}
+
+func newFakeDownload(t testing.TB, size int64) *fakeDownload {
+	d := &fakeDownload{t: t, size: size}
+	fakeAPI(t, d)
+	return d
+}
+
+func (d *fakeDownload) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/sf/v3/Items(fi1)":
+		writeJSON(d.t, w, map[string]interface{}{"Id": "fi1", "odata.type": TypeFile, "FileSizeBytes": d.size})
+	case "/sf/v3/Items(fi1)/Download":
+		writeJSON(d.t, w, map[string]string{"DownloadUrl": "https://acme.sf-api.com/content/fi1"})
+	case "/content/fi1":
+		atomic.AddInt32(&d.requests, 1)
+		if d.fail != nil {
+			if status := d.fail(r); status != 0 {
+				w.WriteHeader(status)
+				return
+			}
+		}
+		var out io.Writer = w
+		if d.rate > 0 {
+			out = &throttledWriter{ctx: r.Context(), w: w, rate: d.rate}
+		}
+		http.ServeContent(&writerOverride{w, out}, r, "fi1", time.Time{}, io.NewSectionReader(zeros{}, 0, d.size))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// Reader of endless zero bytes.
+type zeros struct{}
+
+func (zeros) ReadAt(p []byte, off int64) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
+
+// Response writer sending its body through another writer.
+type writerOverride struct {
+	http.ResponseWriter
+	body io.Writer
+}
+
+func (w *writerOverride) Write(p []byte) (int, error) { return w.body.Write(p) }
+
+// Writer passing on at most rate bytes a second.
+type throttledWriter struct {
+	ctx  context.Context
+	w    io.Writer
+	rate int64
+}
+
+func (t *throttledWriter) Write(p []byte) (int, error) {
+	var written int
+	for len(p) > 0 {
+		n := len(p)
+		if max := int(t.rate / 100); n > max {
+			n = max
+		}
+		select {
+		case <-t.ctx.Done():
+			return written, t.ctx.Err()
+		case <-time.After(time.Duration(float64(n) / float64(t.rate) * float64(time.Second))):
+		}
+		m, err := t.w.Write(p[:n])
+		written += m
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
+
+func TestParallelDownloadPermanentFailure(t *testing.T) {
+	d := newFakeDownload(t, parallelDownloadThreshold)
+	d.rate = 8 << 20
+
+	// The second range is refused; the others are slow enough to still be running when it is.
+	var refused int32
+	d.fail = func(r *http.Request) int {
+		if strings.HasPrefix(r.Header.Get("Range"), fmt.Sprintf("bytes=%d-", parallelDownloadThreshold/4)) {
+			atomic.AddInt32(&refused, 1)
+			return http.StatusForbidden
+		}
+		return 0
+	}
+
+	localPath := filepath.Join(t.TempDir(), "fi1.bin")
+	start := time.Now()
+	_, err := DownloadItem(context.Background(), "fi1", localPath, WithParallelDownload(4))
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, ErrForbidden) {
+		t.Errorf("DownloadItem returned %v, want ErrForbidden", err)
+	}
+	if n := atomic.LoadInt32(&refused); n != 1 {
+		t.Errorf("refused range requested %d times, want once", n)
+	}
+	if elapsed > 2*time.Second {
+		t.Errorf("DownloadItem took %v, want the other ranges stopped", elapsed)
+	}
+}
+
+func TestParallelDownloadRetry(t *testing.T) {
+	d := newFakeDownload(t, parallelDownloadThreshold)
+
+	// The third range fails once with a server error and is tried again after backing off.
+	var mu sync.Mutex
+	failed := false
+	d.fail = func(r *http.Request) int {
+		mu.Lock()
+		defer mu.Unlock()
+		if !failed && strings.HasPrefix(r.Header.Get("Range"), fmt.Sprintf("bytes=%d-", parallelDownloadThreshold/2)) {
+			failed = true
+			return http.StatusInternalServerError
+		}
+		return 0
+	}
+
+	localPath := filepath.Join(t.TempDir(), "fi1.bin")
+	start := time.Now()
+	result, err := DownloadItem(context.Background(), "fi1", localPath, WithParallelDownload(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Bytes != parallelDownloadThreshold {
+		t.Errorf("downloaded %d bytes", result.Bytes)
+	}
+	if elapsed := time.Since(start); elapsed < retryDelay(nil, 1) {
+		t.Errorf("range retried after %v, without backing off", elapsed)
+	}
+}
+
+// Downloads a file over connections throttled to 16 MB/s each; time per download should fall about in step with the
+// number of parts.
+func BenchmarkParallelDownload(b *testing.B) {
+	for _, parts := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("parts=%d", parts), func(b *testing.B) {
+			d := newFakeDownload(b, parallelDownloadThreshold)
+			d.rate = 16 << 20
+			localPath := filepath.Join(b.TempDir(), "fi1.bin")
+
+			b.SetBytes(d.size)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := DownloadItem(context.Background(), "fi1", localPath, WithParallelDownload(parts)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestDownloadItemCancel(t *testing.T) {
+	d := newFakeDownload(t, 100<<20)
+	d.rate = 16 << 20
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var cancelled time.Time
+	progress := func(done, total int64) {
+		if done >= 1<<20 && cancelled.IsZero() {
+			cancelled = time.Now()
+			cancel()
+		}
+	}
+
+	dir := t.TempDir()
+	_, err := DownloadItem(ctx, "fi1", filepath.Join(dir, "fi1.bin"), WithDownloadProgress(progress))
+	returned := time.Now()
+
+	if cancelled.IsZero() {
+		t.Fatalf("download ended before 1 MB arrived: %v", err)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("DownloadItem returned %v, want the context's error", err)
+	}
+	if took := returned.Sub(cancelled); took > 100*time.Millisecond {
+		t.Errorf("DownloadItem returned %v after cancellation", took)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("cancelled download left %s behind", entries[0].Name())
+	}
+}
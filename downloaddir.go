@@ -0,0 +1,77 @@
+package go-sharefile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// DownloadReport is the outcome of DownloadFolder. Paths are relative to the downloaded folder, with "/" separators.
+type DownloadReport struct {
+	Downloaded []string
+	Skipped    []string
+	Failed     map[string]error
+}
+
+// DownloadFolder downloads the files below a remote folder into a local directory, creating the matching directory
+// structure. The download options apply to every file, so WithSkipIfCurrent leaves files already up to date alone. A
+// failed file or folder doesn't stop the others: the report lists what happened to every path, and the returned error
+// joins the failures.
+func DownloadFolder(ctx context.Context, folderID, localDir string, opts ...DownloadOption) (*DownloadReport, error) {
+	report := &DownloadReport{Failed: map[string]error{}}
+
+	if err := os.MkdirAll(localDir, 0755); err != nil {
+		return report, err
+	}
+
+	err := Walk(ctx, folderID, ListOptions{}, func(p string, item *Item) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		local := filepath.Join(localDir, filepath.FromSlash(p))
+		if item.IsFolder() {
+			if err := os.MkdirAll(local, 0755); err != nil {
+				report.Failed[p] = err
+				return SkipFolder
+			}
+			return nil
+		}
+		if !item.IsFile() {
+			return nil
+		}
+
+		result, err := DownloadItem(ctx, item.ID, local, opts...)
+		switch {
+		case err != nil:
+			report.Failed[p] = err
+		case result.Skipped:
+			report.Skipped = append(report.Skipped, p)
+		default:
+			report.Downloaded = append(report.Downloaded, p)
+		}
+		return nil
+	})
+
+	sort.Strings(report.Downloaded)
+	sort.Strings(report.Skipped)
+
+	paths := make([]string, 0, len(report.Failed))
+	for p := range report.Failed {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	errs := make([]error, 0, len(paths)+1)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	for _, p := range paths {
+		errs = append(errs, fmt.Errorf("%s: %w", p, report.Failed[p]))
+	}
+
+	return report, errors.Join(errs...)
+}
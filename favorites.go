@@ -0,0 +1,112 @@
+package go-sharefile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// FavoriteFolder is a folder pinned by a user, with its position in the user's favorites list.
+type FavoriteFolder struct {
+	Item
+	SortOrder int
+}
+
+// Favorite folder as returned by the FavoriteFolders endpoints, internal package use.
+type favoriteFolderBody struct {
+	SortOrder int   `json:"SortOrder"`
+	Item      *Item `json:"Item"`
+}
+
+// Collection response wrapping a list of favorite folders, internal package use.
+type favoriteFolderFeed struct {
+	Favorites []favoriteFolderBody `json:"value"`
+}
+
+// Returns the ID of the authenticated user, internal package use.
+func currentUserID(ctx context.Context) (string, error) {
+	var user struct {
+		ID string `json:"Id"`
+	}
+	if err := call(ctx, "GET", "/sf/v3/Users?$select=Id", nil, &user); err != nil {
+		return "", err
+	}
+
+	return user.ID, nil
+}
+
+// Favorites returns the current user's favorite folders, in display order.
+func Favorites(ctx context.Context) ([]FavoriteFolder, error) {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return UserFavorites(ctx, userID)
+}
+
+// AddFavorite pins a folder to the current user's favorites. Adding a folder that is already a favorite succeeds.
+func AddFavorite(ctx context.Context, folderID string) error {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return AddUserFavorite(ctx, userID, folderID)
+}
+
+// RemoveFavorite unpins a folder from the current user's favorites.
+func RemoveFavorite(ctx context.Context, folderID string) error {
+	userID, err := currentUserID(ctx)
+	if err != nil {
+		return err
+	}
+
+	return RemoveUserFavorite(ctx, userID, folderID)
+}
+
+// UserFavorites returns another user's favorite folders, in display order. Requires administrator rights.
+func UserFavorites(ctx context.Context, userID string) ([]FavoriteFolder, error) {
+	uriPath := fmt.Sprintf("/sf/v3/Users(%s)/FavoriteFolders?$expand=Item", userID)
+
+	var feed favoriteFolderFeed
+	if err := call(ctx, "GET", uriPath, nil, &feed); err != nil {
+		return nil, err
+	}
+
+	favorites := make([]FavoriteFolder, 0, len(feed.Favorites))
+	for _, f := range feed.Favorites {
+		if f.Item == nil {
+			continue
+		}
+		favorites = append(favorites, FavoriteFolder{Item: *f.Item, SortOrder: f.SortOrder})
+	}
+
+	sort.SliceStable(favorites, func(i, j int) bool {
+		return favorites[i].SortOrder < favorites[j].SortOrder
+	})
+
+	return favorites, nil
+}
+
+// AddUserFavorite pins a folder to another user's favorites. Requires administrator rights.
+func AddUserFavorite(ctx context.Context, userID, folderID string) error {
+	favorite := struct {
+		Item itemRef
+	}{
+		Item: itemRef{ID: folderID},
+	}
+
+	err := call(ctx, "POST", fmt.Sprintf("/sf/v3/Users(%s)/FavoriteFolders", userID), favorite, nil)
+	if errors.Is(err, ErrConflict) {
+		return nil
+	}
+
+	return err
+}
+
+// RemoveUserFavorite unpins a folder from another user's favorites. Requires administrator rights.
+func RemoveUserFavorite(ctx context.Context, userID, folderID string) error {
+	return call(ctx, "DELETE", fmt.Sprintf("/sf/v3/Users(%s)/FavoriteFolders(%s)", userID, folderID), nil, nil)
+}
@@ -0,0 +1,89 @@
+package go-sharefile
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Expr is an OData filter expression for Query.Filter. The functions below build the expressions ShareFile accepts
+// on item listings, quoting their arguments; plain strings can still be used for anything else.
+//
+//	q := NewQuery().Filter(And(NameEndsWith(".pdf"), CreatedAfter(time.Now().AddDate(0, 0, -1))))
+type Expr string
+
+// Layout of datetime literals in filter expressions, internal package use.
+const filterTimeLayout = "2006-01-02T15:04:05"
+
+// Formats a time as an OData datetime literal, internal package use.
+func quoteODataTime(t time.Time) string {
+	return fmt.Sprintf("datetime'%s'", t.UTC().Format(filterTimeLayout))
+}
+
+// NameEq matches items with exactly the given name.
+func NameEq(name string) Expr {
+	return Expr(fmt.Sprintf("Name eq %s", quoteODataString(name)))
+}
+
+// NameStartsWith matches items whose name starts with prefix.
+func NameStartsWith(prefix string) Expr {
+	return Expr(fmt.Sprintf("startswith(Name,%s)", quoteODataString(prefix)))
+}
+
+// NameEndsWith matches items whose name ends with suffix, such as an extension.
+func NameEndsWith(suffix string) Expr {
+	return Expr(fmt.Sprintf("endswith(Name,%s)", quoteODataString(suffix)))
+}
+
+// NameContains matches items whose name contains substr.
+func NameContains(substr string) Expr {
+	return Expr(fmt.Sprintf("substringof(%s,Name)", quoteODataString(substr)))
+}
+
+// CreatedAfter matches items created after t.
+func CreatedAfter(t time.Time) Expr {
+	return Expr(fmt.Sprintf("CreationDate gt %s", quoteODataTime(t)))
+}
+
+// CreatedBefore matches items created before t.
+func CreatedBefore(t time.Time) Expr {
+	return Expr(fmt.Sprintf("CreationDate lt %s", quoteODataTime(t)))
+}
+
+// LargerThan matches files bigger than size bytes.
+func LargerThan(size int64) Expr {
+	return Expr(fmt.Sprintf("FileSizeBytes gt %d", size))
+}
+
+// IsType matches items of an OData type, such as TypeFolder or TypeFile.
+func IsType(odataType string) Expr {
+	return Expr(fmt.Sprintf("isof(%s)", quoteODataString(odataType)))
+}
+
+// And matches items matching every expression.
+func And(exprs ...Expr) Expr {
+	return join(exprs, " and ")
+}
+
+// Or matches items matching any of the expressions.
+func Or(exprs ...Expr) Expr {
+	return join(exprs, " or ")
+}
+
+// Not matches items not matching the expression.
+func Not(expr Expr) Expr {
+	return Expr(fmt.Sprintf("not (%s)", expr))
+}
+
+// Joins expressions with a logical operator, parenthesising each so precedence is explicit, internal package use.
+func join(exprs []Expr, op string) Expr {
+	if len(exprs) == 1 {
+		return exprs[0]
+	}
+
+	parts := make([]string, len(exprs))
+	for i, e := range exprs {
+		parts[i] = fmt.Sprintf("(%s)", e)
+	}
+	return Expr(strings.Join(parts, op))
+}
@@ -0,0 +1,37 @@
+package go-sharefile
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFilterExpressions(t *testing.T) {
+	day := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		expr Expr
+		want string
+	}{
+		{NameEq("O'Brien.pdf"), "Name eq 'O''Brien.pdf'"},
+		{NameEndsWith(".pdf"), "endswith(Name,'.pdf')"},
+		{Not(NameEndsWith(".tmp")), "not (endswith(Name,'.tmp'))"},
+		{Not(IsType(TypeFolder)), "not (isof('ShareFile.Api.Models.Folder'))"},
+		{Not(LargerThan(1024)), "not (FileSizeBytes gt 1024)"},
+		{Not(CreatedAfter(day)), "not (CreationDate gt datetime'2025-06-01T12:00:00')"},
+		{Not(Not(NameEq("a"))), "not (not (Name eq 'a'))"},
+		{And(Not(NameStartsWith("~")), Not(NameContains("draft"))), "(not (startswith(Name,'~'))) and (not (substringof('draft',Name)))"},
+		{Not(Or(NameEq("a"), NameEq("b"))), "not ((Name eq 'a') or (Name eq 'b'))"},
+		{Not(And(CreatedAfter(day), CreatedBefore(day.AddDate(0, 0, 1)))), "not ((CreationDate gt datetime'2025-06-01T12:00:00') and (CreationDate lt datetime'2025-06-02T12:00:00'))"},
+	}
+
+	for _, tt := range tests {
+		if string(tt.expr) != tt.want {
+			t.Errorf("expression = %q, want %q", tt.expr, tt.want)
+		}
+	}
+
+	got := NewQuery().Filter(Not(NameEq("a b"))).Encode()
+	if want := "$filter=not%20(Name%20eq%20'a%20b')"; got != want {
+		t.Errorf("encoded filter = %q, want %q", got, want)
+	}
+}
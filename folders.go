@@ -0,0 +1,191 @@
+package go-sharefile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidName is returned for item names ShareFile would reject or silently rewrite.
+var ErrInvalidName = errors.New("sharefile: invalid item name")
+
+// Characters ShareFile doesn't allow in item names, internal package use.
+const invalidNameChars = `\/:*?"<>|`
+
+// Checks an item name is one ShareFile stores unchanged, internal package use.
+func validateName(name string) error {
+	switch {
+	case name == "", name == ".", name == "..":
+		return fmt.Errorf("%w: %q", ErrInvalidName, name)
+	case strings.ContainsAny(name, invalidNameChars):
+		return fmt.Errorf("%w: %q contains one of %s", ErrInvalidName, name, invalidNameChars)
+	case strings.HasSuffix(name, "."), strings.TrimSpace(name) != name:
+		return fmt.Errorf("%w: %q has leading or trailing spaces or a trailing dot", ErrInvalidName, name)
+	}
+	return nil
+}
+
+// FolderOption configures CreateFolder.
+type FolderOption func(*folderOptions)
+
+// Options collected from FolderOption values, internal package use.
+type folderOptions struct {
+	overwrite   bool
+	passthrough bool
+	rename      bool
+	expiration  *time.Time
+}
+
+// WithOverwrite replaces a folder with the same name already in the parent.
+func WithOverwrite() FolderOption {
+	return func(o *folderOptions) {
+		o.overwrite = true
+	}
+}
+
+// WithFolderConflict sets what happens when the parent already has a folder with the same name. The default is
+// ConflictFail.
+func WithFolderConflict(p ConflictPolicy) FolderOption {
+	return func(o *folderOptions) {
+		o.overwrite = p == ConflictOverwrite
+		o.rename = p == ConflictRename
+	}
+}
+
+// WithPassthrough returns the folder with the same name already in the parent instead of creating a new one.
+func WithPassthrough() FolderOption {
+	return func(o *folderOptions) {
+		o.passthrough = true
+	}
+}
+
+// WithFolderExpiration sets when the new folder expires.
+func WithFolderExpiration(t time.Time) FolderOption {
+	return func(o *folderOptions) {
+		o.expiration = &t
+	}
+}
+
+// Applies folder options over the defaults, internal package use.
+func newFolderOptions(opts []FolderOption) *folderOptions {
+	o := &folderOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Returns the query parameters for the create folder request, internal package use.
+func (o *folderOptions) params() url.Values {
+	params := url.Values{}
+	params.Set("overwrite", strconv.FormatBool(o.overwrite))
+	params.Set("passthrough", strconv.FormatBool(o.passthrough))
+	return params
+}
+
+// EnsureFolderPath makes sure every folder along a "/" separated path exists below rootID, creating any that are
+// missing, and returns the last one. It is safe to call concurrently for overlapping paths: a folder created by
+// someone else in the meantime is looked up again rather than reported as a conflict.
+func EnsureFolderPath(ctx context.Context, rootID, path string) (*Item, error) {
+	var segments []string
+	for _, s := range strings.Split(path, "/") {
+		if s == "" {
+			continue
+		}
+		if err := validateName(s); err != nil {
+			return nil, err
+		}
+		segments = append(segments, s)
+	}
+
+	current, err := GetItemByID(ctx, rootID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range segments {
+		next, err := ChildByName(ctx, current.ID, name)
+		if errors.Is(err, ErrNotFound) {
+			next, err = CreateFolder(ctx, current.ID, name, "")
+			if errors.Is(err, ErrConflict) {
+				next, err = ChildByName(ctx, current.ID, name)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !next.IsFolder() && next.Type != "" {
+			return nil, fmt.Errorf("sharefile: %q in folder %s is not a folder", name, current.ID)
+		}
+		current = next
+	}
+
+	return current, nil
+}
+
+// FindOrCreateFolder returns the child folder of parentID with the given name, compared case-insensitively, creating
+// it when there is none. The boolean reports whether the folder was created. A file with the name is an error rather
+// than a match.
+func FindOrCreateFolder(ctx context.Context, parentID, name, description string) (*Item, bool, error) {
+	if err := validateName(name); err != nil {
+		return nil, false, err
+	}
+
+	folder, err := ChildByName(ctx, parentID, name)
+	if err == nil {
+		return existingFolder(folder, parentID, name)
+	}
+	if !errors.Is(err, ErrNotFound) {
+		return nil, false, err
+	}
+
+	folder, err = CreateFolder(ctx, parentID, name, description)
+	if errors.Is(err, ErrConflict) {
+		folder, err = ChildByName(ctx, parentID, name)
+		if err != nil {
+			return nil, false, err
+		}
+		return existingFolder(folder, parentID, name)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	return folder, true, nil
+}
+
+// Returns a child found by FindOrCreateFolder, refusing one that is a file rather than a folder, internal package use.
+func existingFolder(item *Item, parentID, name string) (*Item, bool, error) {
+	if !item.IsFolder() && item.Type != "" {
+		return nil, false, fmt.Errorf("sharefile: %q in folder %s is not a folder", name, parentID)
+	}
+	return item, false, nil
+}
+
+// ChildCount returns the number of children of a folder, counting both files and subfolders, without listing them.
+// Use FileCount for the number of files alone.
+func ChildCount(ctx context.Context, folderID string) (int, error) {
+	uriPath := withQuery(fmt.Sprintf("/sf/v3/Items(%s)/Children", folderID), NewQuery().Select("Id").Top(0).InlineCount())
+
+	var feed itemFeed
+	if err := call(ctx, "GET", uriPath, nil, &feed); err != nil {
+		return 0, err
+	}
+
+	return feed.Count, nil
+}
+
+// FileCount returns the number of files in a folder as reported by its FileCount field. Unlike ChildCount it leaves
+// out subfolders, notes and links.
+func FileCount(ctx context.Context, folderID string) (int, error) {
+	folder, err := GetItemByID(ctx, folderID, NewQuery().Select("Id", "FileCount"))
+	if err != nil {
+		return 0, err
+	}
+
+	return folder.FileCount, nil
+}
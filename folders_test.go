@@ -0,0 +1,40 @@
+package go-sharefile
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestChildCountAndFileCount(t *testing.T) {
+	fakeAPI(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sf/v3/Items(fo1)/Children":
+			q := r.URL.Query()
+			if q.Get("$top") != "0" || q.Get("$inlinecount") != "allpages" {
+				t.Errorf("children counted with %s, want $top=0 and $inlinecount=allpages", r.URL.RawQuery)
+			}
+			// Two files, a subfolder and a note.
+			writeJSON(t, w, map[string]interface{}{"odata.count": 4, "value": []interface{}{}})
+		case "/sf/v3/Items(fo1)":
+			writeJSON(t, w, map[string]interface{}{"Id": "fo1", "FileCount": 2})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	ctx := context.Background()
+
+	children, err := ChildCount(ctx, "fo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := FileCount(ctx, "fo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if children != 4 || files != 2 {
+		t.Errorf("ChildCount = %d, FileCount = %d, want 4 and 2", children, files)
+	}
+}
@@ -1,3 +1,3 @@
 module go-sharefile
 
-go 1.12
+go 1.20
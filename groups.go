@@ -0,0 +1,275 @@
+package go-sharefile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Group is a distribution group of users, which can be given access to folders as a whole. Contacts, its members, is
+// only filled in when expanded.
+type Group struct {
+	ID          string    `json:"Id"`
+	Name        string    `json:"Name"`
+	URL         string    `json:"url"`
+	IsShared    bool      `json:"IsShared"`
+	MemberCount int       `json:"NumberOfContacts"`
+	Contacts    []Contact `json:"Contacts"`
+}
+
+// Collection response wrapping a list of groups, internal package use.
+type groupFeed struct {
+	Groups []Group `json:"value"`
+}
+
+// Groups returns the distribution groups of the account, fetching as many pages as needed. Expand "Contacts" in the
+// listing query to get the members of each group in the same call, which suits small groups. Sorting isn't
+// supported.
+func Groups(ctx context.Context, opts ListOptions) ([]Group, error) {
+	groups := []Group{}
+	err := opts.pageAll("groups", func(q *Query) (int, error) {
+		var feed groupFeed
+		if err := call(ctx, "GET", withQuery("/sf/v3/Groups", q), nil, &feed); err != nil {
+			return 0, err
+		}
+		groups = append(groups, feed.Groups...)
+		return len(feed.Groups), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+// Reference to a user or group in request bodies, internal package use.
+type principalRef struct {
+	ID string `json:"Id"`
+}
+
+// Struct for use in group POST activities
+type groupBody struct {
+	Name     string
+	IsShared bool
+	Contacts []principalRef
+}
+
+// CreateGroup creates a distribution group with the given members and returns it. Members are user IDs or email
+// addresses, which are looked up with UserByEmail; addresses without a user fail the call, listing them all, before
+// the group is created. Shared groups are visible to every employee rather than only their creator. Whether a
+// duplicate name is allowed is up to the API.
+func CreateGroup(ctx context.Context, name string, members []string, isShared bool) (*Group, error) {
+	refs, err := resolvePrincipals(ctx, members)
+	if err != nil {
+		return nil, err
+	}
+
+	body := groupBody{
+		Name:     name,
+		IsShared: isShared,
+		Contacts: refs,
+	}
+
+	var group Group
+	if err := call(ctx, "POST", "/sf/v3/Groups", body, &group); err != nil {
+		return nil, err
+	}
+
+	return &group, nil
+}
+
+// Turns user IDs and email addresses into principal references, internal package use. Every address that can't be
+// resolved is listed in the error.
+func resolvePrincipals(ctx context.Context, members []string) ([]principalRef, error) {
+	refs := make([]principalRef, 0, len(members))
+	var unknown []string
+	for _, m := range members {
+		if !strings.Contains(m, "@") {
+			refs = append(refs, principalRef{ID: m})
+			continue
+		}
+
+		user, err := UserByEmail(ctx, m)
+		if errors.Is(err, ErrNotFound) {
+			unknown = append(unknown, m)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, principalRef{ID: user.ID})
+	}
+
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("sharefile: no users with the addresses %s: %w", strings.Join(unknown, ", "), ErrNotFound)
+	}
+
+	return refs, nil
+}
+
+// Number of members sent per request when changing group membership, internal package use.
+const groupMemberBatch = 100
+
+// Returns the members of a group, fetching as many pages as needed, internal package use.
+func listGroupMembers(ctx context.Context, groupID string, opts ListOptions) ([]Contact, error) {
+	uriPath := fmt.Sprintf("/sf/v3/Groups(%s)/Contacts", groupID)
+
+	members := []Contact{}
+	err := opts.pageAll("group members", func(q *Query) (int, error) {
+		var feed contactFeed
+		if err := call(ctx, "GET", withQuery(uriPath, q), nil, &feed); err != nil {
+			return 0, err
+		}
+		members = append(members, feed.Contacts...)
+		return len(feed.Contacts), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// AddGroupMembers adds users to a group and returns its member count afterwards. Users already in the group are left
+// as they are.
+func AddGroupMembers(ctx context.Context, groupID string, userIDs []string) (int, error) {
+	return changeGroupMembers(ctx, groupID, userIDs, true)
+}
+
+// RemoveGroupMembers removes users from a group and returns its member count afterwards. Users not in the group are
+// skipped with a log message.
+func RemoveGroupMembers(ctx context.Context, groupID string, userIDs []string) (int, error) {
+	return changeGroupMembers(ctx, groupID, userIDs, false)
+}
+
+// Adds or removes group members in batches, skipping those with nothing to change, internal package use.
+func changeGroupMembers(ctx context.Context, groupID string, userIDs []string, add bool) (int, error) {
+	current, err := listGroupMembers(ctx, groupID, ListOptions{})
+	if err != nil {
+		return 0, err
+	}
+	isMember := make(map[string]bool, len(current))
+	for _, c := range current {
+		isMember[c.ID] = true
+	}
+
+	var refs []principalRef
+	for _, id := range userIDs {
+		switch {
+		case add && !isMember[id]:
+			refs = append(refs, principalRef{ID: id})
+			isMember[id] = true
+		case !add && isMember[id]:
+			refs = append(refs, principalRef{ID: id})
+			isMember[id] = false
+		case !add:
+			Logger.Printf("user %s is not a member of group %s, not removing", id, groupID)
+		}
+	}
+
+	uriPath := fmt.Sprintf("/sf/v3/Groups(%s)/Contacts", groupID)
+	if !add {
+		uriPath += "/Remove"
+	}
+	for len(refs) > 0 {
+		n := len(refs)
+		if n > groupMemberBatch {
+			n = groupMemberBatch
+		}
+		if err := call(ctx, "POST", uriPath, refs[:n], nil); err != nil {
+			return 0, err
+		}
+		refs = refs[n:]
+	}
+
+	var group Group
+	if err := call(ctx, "GET", withQuery(fmt.Sprintf("/sf/v3/Groups(%s)", groupID), NewQuery().Select("Id", "NumberOfContacts")), nil, &group); err != nil {
+		return 0, err
+	}
+
+	return group.MemberCount, nil
+}
+
+// GroupMembers returns the members of a group, fetching as many pages as needed. Sorting isn't supported.
+func GroupMembers(ctx context.Context, groupID string, opts ListOptions) ([]Contact, error) {
+	return listGroupMembers(ctx, groupID, opts)
+}
+
+// Number of members GroupMemberUsers looks up at once when no concurrency is given, internal package use.
+const defaultMemberLookups = 4
+
+// GroupMemberUsers returns the full user records of the members of a group, such as their last sign-in, in the order
+// of GroupMembers. Each member takes a call of its own, at most concurrency at a time; the default is 4. Once ctx is
+// done no further members are looked up.
+func GroupMemberUsers(ctx context.Context, groupID string, concurrency int) ([]User, error) {
+	members, err := listGroupMembers(ctx, groupID, ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if concurrency <= 0 {
+		concurrency = defaultMemberLookups
+	}
+
+	users := make([]User, len(members))
+	errs := make([]error, len(members))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i := range members {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			user, err := GetUser(ctx, members[i].ID, nil)
+			if err != nil {
+				errs[i] = fmt.Errorf("member %s: %w", members[i].ID, err)
+				return
+			}
+			users[i] = *user
+		}(i)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// GroupUpdate holds the fields UpdateGroup changes. Nil fields are left as they are.
+type GroupUpdate struct {
+	Name     *string `json:",omitempty"`
+	IsShared *bool   `json:",omitempty"`
+}
+
+// UpdateGroup renames a group or changes whether it is shared, and returns the updated group.
+func UpdateGroup(ctx context.Context, groupID string, update GroupUpdate) (*Group, error) {
+	var group Group
+	if err := call(ctx, "PATCH", fmt.Sprintf("/sf/v3/Groups(%s)", groupID), update, &group); err != nil {
+		return nil, err
+	}
+
+	return &group, nil
+}
+
+// DeleteGroup deletes a group. Its members keep their accounts, but the access the group was given to folders goes
+// with it: ShareFile removes the group's access control entries. A group that no longer exists gives an error
+// wrapping ErrNotFound, which callers deleting idempotently can ignore.
+func DeleteGroup(ctx context.Context, groupID string) error {
+	return call(ctx, "DELETE", fmt.Sprintf("/sf/v3/Groups(%s)", groupID), nil, nil)
+}
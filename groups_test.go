@@ -0,0 +1,81 @@
+package go-sharefile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// Serves a group, g1, of n members, u0 to u(n-1), whose user records are answered by user.
+func fakeGroup(t *testing.T, n int, user http.HandlerFunc) {
+	fakeAPI(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/sf/v3/Groups(g1)/Contacts":
+			var members []Contact
+			if r.URL.Query().Get("$skip") == "" {
+				for i := 0; i < n; i++ {
+					members = append(members, Contact{ID: fmt.Sprintf("u%d", i)})
+				}
+			}
+			writeJSON(t, w, map[string]interface{}{"value": members})
+		case strings.HasPrefix(r.URL.Path, "/sf/v3/Users("):
+			user(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestGroupMemberUsers(t *testing.T) {
+	var running, most int32
+	fakeGroup(t, 10, func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&most)
+			if n <= m || atomic.CompareAndSwapInt32(&most, m, n) {
+				break
+			}
+		}
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/sf/v3/Users("), ")")
+		writeJSON(t, w, map[string]string{"Id": id})
+	})
+
+	users, err := GroupMemberUsers(context.Background(), "g1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, u := range users {
+		if want := fmt.Sprintf("u%d", i); u.ID != want {
+			t.Errorf("user %d is %s, want %s", i, u.ID, want)
+		}
+	}
+	if most > defaultMemberLookups {
+		t.Errorf("%d lookups at once, want at most %d", most, defaultMemberLookups)
+	}
+}
+
+func TestGroupMemberUsersCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The first lookup hangs until the caller gives up, leaving the rest waiting for it.
+	var lookups int32
+	fakeGroup(t, 10, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&lookups, 1)
+		cancel()
+		<-r.Context().Done()
+	})
+
+	_, err := GroupMemberUsers(ctx, "g1", 1)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("GroupMemberUsers returned %v, want the context's error", err)
+	}
+	if n := atomic.LoadInt32(&lookups); n != 1 {
+		t.Errorf("%d members looked up after cancellation, want only the first", n)
+	}
+}
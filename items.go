@@ -0,0 +1,362 @@
+package go-sharefile
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OData types the API reports for items.
+const (
+	TypeFolder = "ShareFile.Api.Models.Folder"
+	TypeFile   = "ShareFile.Api.Models.File"
+	TypeNote   = "ShareFile.Api.Models.Note"
+)
+
+// Item is a ShareFile item, such as a folder or a file. Fields left out of the response stay at their zero value.
+//
+// ProgenyEditDate is when anything below a folder last changed. ClientCreatedDate and ClientModifiedDate are a file's
+// times on the machine it was uploaded from. Creator, the item's owner, Zone, the storage zone holding it, and LockedBy,
+// the user who checked a file out, are only filled in when expanded. Skipped isn't part of the API: it is set on the
+// existing file returned by an upload given WithSkipIfUnchanged that found nothing to upload.
+type Item struct {
+	ID                 string       `json:"Id"`
+	Type               string       `json:"odata.type"`
+	Name               string       `json:"Name"`
+	FileName           string       `json:"FileName"`
+	Description        string       `json:"Description"`
+	CreationDate       time.Time    `json:"CreationDate"`
+	FileSizeBytes      int64        `json:"FileSizeBytes"`
+	Hash               string       `json:"Hash"`
+	FileCount          int          `json:"FileCount"`
+	IsDeleted          bool         `json:"IsDeleted"`
+	Redirection        *Redirection `json:"Redirection"`
+	ExpirationDate     time.Time    `json:"ExpirationDate"`
+	VirusStatus        ScanResult   `json:"VirusStatus"`
+	ProgenyEditDate    time.Time    `json:"ProgenyEditDate"`
+	ClientCreatedDate  time.Time    `json:"ClientCreatedDate"`
+	ClientModifiedDate time.Time    `json:"ClientModifiedDate"`
+	Parent             *Item        `json:"Parent"`
+	Creator            *User        `json:"Creator"`
+	Zone               *Zone        `json:"Zone"`
+	LockedBy           *User        `json:"LockedBy"`
+	Children           []Item       `json:"Children"`
+	Skipped            bool         `json:"-"`
+}
+
+// NeverExpires is the expiration date ShareFile uses for items that don't expire. Set it through ItemUpdate to clear
+// an expiration.
+var NeverExpires = time.Date(9999, time.December, 31, 23, 59, 59, 999999900, time.UTC)
+
+// Expires reports whether the item has an expiration date.
+func (i *Item) Expires() bool {
+	return !i.ExpirationDate.IsZero() && i.ExpirationDate.Year() < NeverExpires.Year()
+}
+
+// IsFolder reports whether the item is a folder.
+func (i *Item) IsFolder() bool {
+	return i.Type == TypeFolder
+}
+
+// IsFile reports whether the item is a file.
+func (i *Item) IsFile() bool {
+	return i.Type == TypeFile
+}
+
+// IsNote reports whether the item is a note.
+func (i *Item) IsNote() bool {
+	return i.Type == TypeNote
+}
+
+// Reference to an item in request bodies, internal package use.
+type itemRef struct {
+	ID string `json:"Id"`
+}
+
+// Collection response wrapping a list of items, internal package use.
+type itemFeed struct {
+	Count    int    `json:"odata.count"`
+	NextLink string `json:"odata.nextLink"`
+	Items    []Item `json:"value"`
+}
+
+// Links holds the URLs for opening an item outside the API. Fields are empty when the account has the corresponding
+// feature disabled.
+type Links struct {
+	// WebView opens the item in the ShareFile web app.
+	WebView string
+	// Protocol opens the item directly in the ShareFile desktop apps.
+	Protocol string
+	// Edit opens the item for online editing, when the file type supports it.
+	Edit string
+}
+
+// Platforms accepted by the ProtocolLinks endpoint, internal package use.
+const (
+	platformWeb     = "Web"
+	platformWindows = "Windows"
+	platformEdit    = "WebEdit"
+)
+
+// Protocol link response, internal package use.
+type protocolLink struct {
+	Link string `json:"Link"`
+}
+
+// ItemLinks returns the web view, desktop protocol and online editing links for an item.
+func ItemLinks(ctx context.Context, itemID string) (*Links, error) {
+	var (
+		links Links
+		err   error
+	)
+
+	if links.WebView, err = itemProtocolLink(ctx, itemID, platformWeb); err != nil {
+		return nil, err
+	}
+	if links.Protocol, err = itemProtocolLink(ctx, itemID, platformWindows); err != nil {
+		return nil, err
+	}
+	if links.Edit, err = itemProtocolLink(ctx, itemID, platformEdit); err != nil {
+		return nil, err
+	}
+
+	return &links, nil
+}
+
+// Returns the protocol link for one platform, or an empty string when the account or item doesn't offer it,
+// internal package use.
+func itemProtocolLink(ctx context.Context, itemID, platform string) (string, error) {
+	uriPath := fmt.Sprintf("/sf/v3/Items(%s)/ProtocolLinks(%s)", itemID, platform)
+
+	var link protocolLink
+	err := call(ctx, "GET", uriPath, nil, &link)
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound:
+			// Preview and editing are per-account features; the API refuses the platform when they're disabled.
+			return "", nil
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return link.Link, nil
+}
+
+// Breadcrumbs returns the ancestors of an item, ordered from the root down to its immediate parent.
+//
+// Items without a browsable path, such as those in the recycle bin or in another user's file box, return just their
+// parent when the API reports one.
+func Breadcrumbs(ctx context.Context, itemID string) ([]Item, error) {
+	var feed itemFeed
+	err := call(ctx, "GET", fmt.Sprintf("/sf/v3/Items(%s)/Breadcrumbs", itemID), nil, &feed)
+	if err != nil && !errors.Is(err, ErrNotFound) && !errors.Is(err, ErrForbidden) {
+		return nil, err
+	}
+
+	crumbs := feed.Items
+	if n := len(crumbs); n > 0 && crumbs[n-1].ID == itemID {
+		crumbs = crumbs[:n-1]
+	}
+	if len(crumbs) > 0 {
+		return crumbs, nil
+	}
+
+	item, err := GetItemByID(ctx, itemID, NewQuery().Expand("Parent"))
+	if err != nil {
+		return nil, err
+	}
+	if item.Parent == nil {
+		return []Item{}, nil
+	}
+
+	return []Item{*item.Parent}, nil
+}
+
+// FullPath returns the path of an item, made of the names of its ancestors and its own name joined with "/".
+func FullPath(ctx context.Context, itemID string) (string, error) {
+	item, err := GetItemByID(ctx, itemID, NewQuery().Select("Id", "Name"))
+	if err != nil {
+		return "", err
+	}
+
+	crumbs, err := Breadcrumbs(ctx, itemID)
+	if err != nil {
+		return "", err
+	}
+
+	names := make([]string, 0, len(crumbs)+1)
+	for _, c := range crumbs {
+		names = append(names, c.Name)
+	}
+	names = append(names, item.Name)
+
+	return "/" + strings.Join(names, "/"), nil
+}
+
+// Children returns the children of a folder. The query may be nil.
+//
+// When the query includes deleted items but the caller isn't allowed to see them, only live items are returned and a
+// warning is logged.
+func Children(ctx context.Context, folderID string, q *Query) ([]Item, error) {
+	feed, err := listChildren(ctx, folderID, q)
+	if err != nil {
+		return nil, err
+	}
+
+	return feed.Items, nil
+}
+
+// Fetches one page of children, falling back to live items only when deleted items are refused, internal package use.
+func listChildren(ctx context.Context, folderID string, q *Query) (*itemFeed, error) {
+	uriPath := fmt.Sprintf("/sf/v3/Items(%s)/Children", folderID)
+
+	var feed itemFeed
+	err := call(ctx, "GET", withQuery(uriPath, q), nil, &feed)
+	if errors.Is(err, ErrForbidden) && q != nil && q.deleted {
+		Logger.Printf("deleted items in folder %s not visible to the caller, listing live items only", folderID)
+
+		live := q.clone()
+		live.deleted = false
+		err = call(ctx, "GET", withQuery(uriPath, live), nil, &feed)
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest && q != nil && q.filter != "" {
+		return nil, fmt.Errorf("sharefile: filter %q rejected: %w", q.filter, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &feed, nil
+}
+
+// Largest number of items RecentItems returns.
+const maxRecentItems = 500
+
+// RecentItems returns up to maxItems of the current user's recently accessed items, most recent first, with their
+// parent folders. Accounts without the recent items feature get an empty list.
+func RecentItems(ctx context.Context, maxItems int) ([]Item, error) {
+	if maxItems <= 0 || maxItems > maxRecentItems {
+		maxItems = maxRecentItems
+	}
+
+	uriPath := withQuery("/sf/v3/Items/Recent", NewQuery().Expand("Parent").Top(maxItems))
+
+	var feed itemFeed
+	err := call(ctx, "GET", uriPath, nil, &feed)
+	if errors.Is(err, ErrForbidden) || errors.Is(err, ErrNotFound) {
+		return []Item{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return feed.Items, nil
+}
+
+// ItemExists reports whether the folder has a child with the given name, returning the child when it does. Nothing is
+// downloaded; a missing child or folder is not an error.
+func ItemExists(ctx context.Context, folderID, name string) (bool, *Item, error) {
+	item, err := ChildByName(ctx, folderID, name)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil, nil
+	}
+	if err != nil {
+		return false, nil, err
+	}
+
+	return true, item, nil
+}
+
+// ItemInfo describes what the current user can do with an item. Raw holds every field of the response, including
+// capabilities without a field of their own.
+type ItemInfo struct {
+	CanView              bool `json:"CanView"`
+	CanUpload            bool `json:"CanUpload"`
+	CanDownload          bool `json:"CanDownload"`
+	CanDeleteCurrentItem bool `json:"CanDeleteCurrentItem"`
+	CanDeleteChildItems  bool `json:"CanDeleteChildItems"`
+	CanAddFolder         bool `json:"CanAddFolder"`
+	CanManagePermissions bool `json:"CanManagePermissions"`
+	CanShare             bool `json:"CanShare"`
+	IsShared             bool `json:"IsSharedFolder"`
+
+	Raw map[string]interface{} `json:"-"`
+}
+
+// UnmarshalJSON decodes the known capabilities and keeps the full response in Raw.
+func (i *ItemInfo) UnmarshalJSON(b []byte) error {
+	type plain ItemInfo
+	if err := json.Unmarshal(b, (*plain)(i)); err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, &i.Raw)
+}
+
+// GetItemInfo returns the current user's capabilities on an item.
+func GetItemInfo(ctx context.Context, itemID string) (*ItemInfo, error) {
+	var info ItemInfo
+	if err := call(ctx, "GET", fmt.Sprintf("/sf/v3/Items(%s)/Info", itemID), nil, &info); err != nil {
+		return nil, err
+	}
+
+	return &info, nil
+}
+
+// AmbiguousNameError is returned by ChildByName when several children have the name, differing only in case.
+type AmbiguousNameError struct {
+	Name  string
+	Items []Item
+}
+
+func (e *AmbiguousNameError) Error() string {
+	return fmt.Sprintf("sharefile: %d children named %q, differing only in case", len(e.Items), e.Name)
+}
+
+// ChildByName returns the child of a folder with the given name. Names are compared case-insensitively, as ShareFile
+// does; when several children match, an *AmbiguousNameError holding all of them is returned.
+// A missing child is an error wrapping ErrNotFound.
+//
+// The lookup uses a filtered query, falling back to scanning the folder page by page if the filter is rejected.
+func ChildByName(ctx context.Context, folderID, name string) (*Item, error) {
+	children, err := Children(ctx, folderID, NewQuery().Filter(NameEq(name)))
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest {
+		children, err = nil, nil
+		it := NewChildIterator(ctx, folderID, ListOptions{})
+		for it.Next() {
+			children = append(children, *it.Item())
+		}
+		err = it.Err()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []Item
+	for _, c := range children {
+		if strings.EqualFold(c.Name, name) {
+			matches = append(matches, c)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("sharefile: %q in folder %s: %w", name, folderID, ErrNotFound)
+	case 1:
+		return &matches[0], nil
+	}
+
+	return nil, &AmbiguousNameError{Name: name, Items: matches}
+}
@@ -0,0 +1,49 @@
+package go-sharefile
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestSelectPresets(t *testing.T) {
+	tests := []struct {
+		fields []string
+		want   string
+	}{
+		{MinimalFields, "$select=Id,Name,CreationDate,FileSizeBytes"},
+		{AllFields, "$select=*"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			var got string
+			fakeAPI(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.URL.RawQuery
+				writeJSON(t, w, map[string]interface{}{
+					"Id":            "fi1",
+					"Name":          "a.pdf",
+					"CreationDate":  "2025-01-02T03:04:05Z",
+					"FileSizeBytes": 42,
+					// Fields Item has no room for are dropped.
+					"StreamID":                  "st1",
+					"PreviewPlatformsSupported": map[string]interface{}{"Web": true},
+				})
+			}))
+
+			item, err := GetItemByID(context.Background(), "fi1", NewQuery().Select(tt.fields...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("query = %q, want %q", got, tt.want)
+			}
+
+			created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+			if item.ID != "fi1" || item.Name != "a.pdf" || !item.CreationDate.Equal(created) || item.FileSizeBytes != 42 {
+				t.Errorf("decoded %+v", item)
+			}
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package go-sharefile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Number of results requested per page when ListOptions doesn't set one.
+const defaultPageSize = 500
+
+// SortField is an item field listings can be sorted by on the server.
+type SortField string
+
+// Fields ShareFile sorts item listings by.
+const (
+	SortByName            SortField = "Name"
+	SortByCreationDate    SortField = "CreationDate"
+	SortByFileSize        SortField = "FileSizeBytes"
+	SortByProgenyEditDate SortField = "ProgenyEditDate"
+)
+
+// ListOptions controls the paged listing calls.
+type ListOptions struct {
+	// Query adds $select, $filter and listing parameters to every page request. Its $top and $skip are replaced by
+	// the paging.
+	Query *Query
+	// PageSize is the number of results fetched per request. Zero uses a default of 500.
+	PageSize int
+	// SortBy sorts the results on the server, in descending order when SortDescending is set.
+	SortBy         SortField
+	SortDescending bool
+}
+
+// Returns the page size to request, internal package use.
+func (o ListOptions) pageSize() int {
+	if o.PageSize <= 0 {
+		return defaultPageSize
+	}
+	return o.PageSize
+}
+
+// Returns the query for the page starting at skip, internal package use.
+func (o ListOptions) pageQuery(skip int) (*Query, error) {
+	q := o.Query.clone()
+
+	switch o.SortBy {
+	case "":
+	case SortByName, SortByCreationDate, SortByFileSize, SortByProgenyEditDate:
+		// Id breaks ties so items with equal sort keys keep their order from one page to the next.
+		q.OrderBy(string(o.SortBy), o.SortDescending).OrderBy("Id", false)
+	default:
+		return nil, fmt.Errorf("sharefile: cannot sort by %q", o.SortBy)
+	}
+
+	return q.Top(o.pageSize()).Skip(skip), nil
+}
+
+// Requests the pages of a listing that can't be sorted until one comes back short, internal package use. fetch is
+// given each page's query and returns the number of results on the page.
+func (o ListOptions) pageAll(what string, fetch func(q *Query) (int, error)) error {
+	if o.SortBy != "" {
+		return fmt.Errorf("sharefile: cannot sort %s by %q", what, o.SortBy)
+	}
+
+	size := o.pageSize()
+	for skip := 0; ; skip += size {
+		n, err := fetch(o.Query.clone().Top(size).Skip(skip))
+		if err != nil {
+			return err
+		}
+		if n < size {
+			return nil
+		}
+	}
+}
+
+// ChildIterator pages through the children of a folder, fetching a page at a time as Next is called.
+//
+//	it := NewChildIterator(ctx, folderID, ListOptions{})
+//	for it.Next() {
+//		item := it.Item()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type ChildIterator struct {
+	ctx      context.Context
+	folderID string
+	list     func(ctx context.Context, q *Query) (*itemFeed, error)
+	opts     ListOptions
+	page     []Item
+	index    int
+	skip     int
+	done     bool
+	err      error
+}
+
+// NewChildIterator returns an iterator over the children of a folder.
+func NewChildIterator(ctx context.Context, folderID string, opts ListOptions) *ChildIterator {
+	return &ChildIterator{
+		ctx:      ctx,
+		folderID: folderID,
+		list: func(ctx context.Context, q *Query) (*itemFeed, error) {
+			return listChildren(ctx, folderID, q)
+		},
+		opts:  opts,
+		index: -1,
+	}
+}
+
+// Next advances to the next child, fetching the next page when needed. It returns false when there are no more
+// children or an error occurred.
+func (it *ChildIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	it.index++
+	if it.index < len(it.page) {
+		return true
+	}
+	if it.done {
+		return false
+	}
+
+	size := it.opts.pageSize()
+	q, err := it.opts.pageQuery(it.skip)
+	if err != nil {
+		it.err = err
+		return false
+	}
+
+	feed, err := it.list(it.ctx, q)
+	if err != nil {
+		it.err = err
+		return false
+	}
+
+	it.page = feed.Items
+	it.index = 0
+	it.skip += len(feed.Items)
+	it.done = len(feed.Items) < size
+
+	return len(it.page) > 0
+}
+
+// Item returns the current child.
+func (it *ChildIterator) Item() *Item {
+	return &it.page[it.index]
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *ChildIterator) Err() error {
+	return it.err
+}
+
+// SkipFolder can be returned by a WalkFunc to skip the children of the folder it was called for.
+var SkipFolder = errors.New("skip this folder")
+
+// WalkFunc is called by Walk for every item below the starting folder, with the item's path relative to it.
+// Returning SkipFolder for a folder skips its children; any other error stops the walk and is returned by Walk.
+type WalkFunc func(path string, item *Item) error
+
+// Walk visits every item below a folder, depth first, calling fn for each. The listing options apply to every folder
+// visited. A $filter in them only limits the items fn is called for: every subfolder is still walked, and its children
+// are reported before Walk descends into its subfolders. A $select always keeps Id and Name.
+func Walk(ctx context.Context, folderID string, opts ListOptions, fn WalkFunc) error {
+	if opts.Query == nil || (opts.Query.filter == "" && len(opts.Query.selects) == 0) {
+		return walk(ctx, folderID, "", opts, fn)
+	}
+
+	q := opts.Query.clone()
+	if len(q.selects) > 0 && !containsField(q.selects, "*") {
+		// Paths need the names, and skipping needs the IDs.
+		for _, f := range []string{"Id", "Name"} {
+			if !containsField(q.selects, f) {
+				q.selects = append(q.selects, f)
+			}
+		}
+	}
+	opts.Query = q
+
+	return walkFiltered(ctx, folderID, "", opts, fn)
+}
+
+// Reports whether a $select list names field, internal package use.
+func containsField(fields []string, field string) bool {
+	for _, f := range fields {
+		if strings.EqualFold(f, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// Walks the children of one folder, internal package use.
+func walk(ctx context.Context, folderID, dir string, opts ListOptions, fn WalkFunc) error {
+	it := NewChildIterator(ctx, folderID, opts)
+	for it.Next() {
+		item := it.Item()
+		p := path.Join(dir, item.Name)
+
+		err := fn(p, item)
+		if err == SkipFolder {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if item.IsFolder() {
+			if err := walk(ctx, item.ID, p, opts, fn); err != nil {
+				return err
+			}
+		}
+	}
+
+	return it.Err()
+}
+
+// Walks the children of one folder when the listing is filtered or narrowed by $select, internal package use. The
+// subfolders are listed on their own, so the walk reaches those the filter leaves out and doesn't depend on the
+// selected fields to tell folders apart.
+func walkFiltered(ctx context.Context, folderID, dir string, opts ListOptions, fn WalkFunc) error {
+	fq := opts.Query.clone()
+	fq.selects = []string{"Id", "Name"}
+	fq.expands = nil
+	fq.filter = string(IsType(TypeFolder))
+	folderOpts := opts
+	folderOpts.Query = fq
+
+	var folders []Item
+	isFolder := map[string]bool{}
+	fit := NewChildIterator(ctx, folderID, folderOpts)
+	for fit.Next() {
+		folders = append(folders, *fit.Item())
+		isFolder[fit.Item().ID] = true
+	}
+	if err := fit.Err(); err != nil {
+		return err
+	}
+
+	skipped := map[string]bool{}
+	it := NewChildIterator(ctx, folderID, opts)
+	for it.Next() {
+		item := it.Item()
+		if item.Type == "" && isFolder[item.ID] {
+			item.Type = TypeFolder
+		}
+
+		err := fn(path.Join(dir, item.Name), item)
+		if err == SkipFolder {
+			skipped[item.ID] = true
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+
+	for _, f := range folders {
+		if skipped[f.ID] {
+			continue
+		}
+		if err := walkFiltered(ctx, f.ID, path.Join(dir, f.Name), opts, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
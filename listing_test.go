@@ -0,0 +1,147 @@
+package go-sharefile
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestListOptionsOrderBy(t *testing.T) {
+	tests := []struct {
+		opts ListOptions
+		want string
+	}{
+		{ListOptions{PageSize: 10}, "$top=10"},
+		{ListOptions{PageSize: 10, SortBy: SortByName}, "$orderby=Name,Id&$top=10"},
+		{ListOptions{PageSize: 10, SortBy: SortByName, SortDescending: true}, "$orderby=Name%20desc,Id&$top=10"},
+		{ListOptions{PageSize: 10, SortBy: SortByFileSize, SortDescending: true}, "$orderby=FileSizeBytes%20desc,Id&$top=10"},
+		{
+			ListOptions{Query: NewQuery().Select("Id"), PageSize: 10, SortBy: SortByCreationDate, SortDescending: true},
+			"$select=Id&$orderby=CreationDate%20desc,Id&$top=10",
+		},
+	}
+
+	for _, tt := range tests {
+		q, err := tt.opts.pageQuery(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := q.Encode(); got != tt.want {
+			t.Errorf("%+v: query = %q, want %q", tt.opts, got, tt.want)
+		}
+	}
+
+	if got := NewQuery().OrderBy("Name", true).OrderBy("CreationDate", false).Encode(); got != "$orderby=Name%20desc,CreationDate" {
+		t.Errorf("OrderBy = %q", got)
+	}
+
+	if _, err := (ListOptions{SortBy: "Hash"}).pageQuery(0); err == nil {
+		t.Error("sorting by an unsupported field succeeded")
+	}
+}
+
+// Serves a tree of folders whose listings honour the two filters TestWalkFilter uses and leave out every field a
+// $select doesn't name, the item type included.
+func fakeTree(t *testing.T) {
+	type node struct {
+		id, name string
+		folder   bool
+	}
+	children := map[string][]node{
+		"fo0": {{"fi1", "a.pdf", false}, {"fi2", "b.txt", false}, {"fo1", "docs", true}, {"fo3", "archive.pdf", true}},
+		"fo1": {{"fi3", "c.pdf", false}, {"fi4", "d.doc", false}, {"fo2", "sub", true}},
+		"fo2": {{"fi5", "e.pdf", false}},
+		"fo3": {{"fi6", "f.pdf", false}},
+	}
+
+	fakeAPI(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/sf/v3/Items("), ")/Children")
+		q := r.URL.Query()
+		var selects []string
+		if s := q.Get("$select"); s != "" {
+			selects = strings.Split(s, ",")
+		}
+
+		items := []map[string]interface{}{}
+		for _, n := range children[id] {
+			switch q.Get("$filter") {
+			case "":
+			case "endswith(Name,'.pdf')":
+				if !strings.HasSuffix(n.name, ".pdf") {
+					continue
+				}
+			case "isof('ShareFile.Api.Models.Folder')":
+				if !n.folder {
+					continue
+				}
+			default:
+				t.Errorf("unexpected filter %q", q.Get("$filter"))
+			}
+
+			typ := TypeFile
+			if n.folder {
+				typ = TypeFolder
+			}
+			item := map[string]interface{}{"Id": n.id, "Name": n.name, "odata.type": typ, "FileSizeBytes": 1}
+			if selects != nil {
+				narrowed := map[string]interface{}{}
+				for _, f := range selects {
+					if v, ok := item[f]; ok {
+						narrowed[f] = v
+					}
+				}
+				item = narrowed
+			}
+			items = append(items, item)
+		}
+		writeJSON(t, w, map[string]interface{}{"value": items})
+	}))
+}
+
+func TestWalkFilter(t *testing.T) {
+	tests := []struct {
+		name  string
+		query *Query
+		want  []string
+	}{
+		{"unfiltered", nil, []string{
+			"a.pdf", "b.txt", "docs", "docs/c.pdf", "docs/d.doc", "docs/sub", "docs/sub/e.pdf", "archive.pdf",
+		}},
+		{"filter", NewQuery().Filter(NameEndsWith(".pdf")), []string{
+			"a.pdf", "archive.pdf", "docs/c.pdf", "docs/sub/e.pdf",
+		}},
+		{"filter and select", NewQuery().Filter(NameEndsWith(".pdf")).Select("FileSizeBytes"), []string{
+			"a.pdf", "archive.pdf", "docs/c.pdf", "docs/sub/e.pdf",
+		}},
+		{"select", NewQuery().Select("FileSizeBytes"), []string{
+			"a.pdf", "b.txt", "docs", "archive.pdf", "docs/c.pdf", "docs/d.doc", "docs/sub", "docs/sub/e.pdf",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeTree(t)
+
+			var got []string
+			err := Walk(context.Background(), "fo0", ListOptions{Query: tt.query}, func(p string, item *Item) error {
+				got = append(got, p)
+				// A matching folder can still be skipped, even when the $select left out its type.
+				if item.Name == "archive.pdf" {
+					if !item.IsFolder() {
+						t.Errorf("%s not reported as a folder", p)
+					}
+					return SkipFolder
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("walked %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package go-sharefile
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// Metadata entry as sent and received by the Items(id)/Metadata endpoints, internal package use.
+type metadataEntry struct {
+	Name     string `json:"Name"`
+	Value    string `json:"Value"`
+	IsPublic bool   `json:"IsPublic"`
+}
+
+// Collection response wrapping a list of metadata entries, internal package use.
+type metadataFeed struct {
+	Entries []metadataEntry `json:"value"`
+}
+
+// ItemMetadata returns the custom metadata of an item as name/value pairs.
+func ItemMetadata(ctx context.Context, itemID string) (map[string]string, error) {
+	var feed metadataFeed
+	if err := call(ctx, "GET", fmt.Sprintf("/sf/v3/Items(%s)/Metadata", itemID), nil, &feed); err != nil {
+		return nil, err
+	}
+
+	kv := make(map[string]string, len(feed.Entries))
+	for _, e := range feed.Entries {
+		kv[e.Name] = e.Value
+	}
+
+	return kv, nil
+}
+
+// SetItemMetadata sets custom metadata on an item. Entries whose names already exist are overwritten, other entries
+// are left untouched.
+func SetItemMetadata(ctx context.Context, itemID string, kv map[string]string) error {
+	if len(kv) == 0 {
+		return nil
+	}
+
+	entries := make([]metadataEntry, 0, len(kv))
+	for name, value := range kv {
+		entries = append(entries, metadataEntry{Name: name, Value: value})
+	}
+
+	return call(ctx, "POST", fmt.Sprintf("/sf/v3/Items(%s)/Metadata", itemID), entries, nil)
+}
+
+// DeleteItemMetadata removes a single metadata entry from an item.
+func DeleteItemMetadata(ctx context.Context, itemID, name string) error {
+	uriPath := fmt.Sprintf("/sf/v3/Items(%s)/Metadata?name=%s", itemID, url.QueryEscape(name))
+
+	return call(ctx, "DELETE", uriPath, nil, nil)
+}
+
+// UploadFileWithMetadata uploads a file to a folder with UploadFile, then sets metadata on the uploaded item.
+func UploadFileWithMetadata(ctx context.Context, localPath, folderID string, kv map[string]string, opts ...UploadOption) (*Item, error) {
+	item, err := UploadFile(ctx, localPath, folderID, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := SetItemMetadata(ctx, item.ID, kv); err != nil {
+		return item, err
+	}
+
+	return item, nil
+}
@@ -0,0 +1,42 @@
+package go-sharefile
+
+import (
+	"context"
+	"fmt"
+)
+
+// Struct for use in note POST/PATCH activities
+type noteBody struct {
+	Name        string `json:",omitempty"`
+	Description string
+}
+
+// CreateNote creates a note in the given parent folder. The contents are stored as given, so HTML markup is kept
+// intact.
+func CreateNote(ctx context.Context, parentID, name, contents string) (*Item, error) {
+	note := noteBody{
+		Name:        name,
+		Description: contents,
+	}
+
+	var item Item
+	if err := call(ctx, "POST", fmt.Sprintf("/sf/v3/Items(%s)/Note", parentID), note, &item); err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+// UpdateNote replaces the contents of an existing note.
+func UpdateNote(ctx context.Context, noteID, contents string) (*Item, error) {
+	note := noteBody{
+		Description: contents,
+	}
+
+	var item Item
+	if err := call(ctx, "PATCH", fmt.Sprintf("/sf/v3/Items(%s)", noteID), note, &item); err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
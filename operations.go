@@ -0,0 +1,121 @@
+package go-sharefile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrOperationFailed is returned by WaitForOperation when the operation ends without succeeding.
+var ErrOperationFailed = errors.New("sharefile: operation failed")
+
+// States of an AsyncOperation.
+const (
+	OperationScheduled  = "Scheduled"
+	OperationInProgress = "InProgress"
+	OperationSuccess    = "Success"
+	OperationFailed     = "Failed"
+	OperationCancelled  = "Cancelled"
+)
+
+// Longest wait between two polls of WaitForOperation, internal package use.
+const maxOperationPoll = time.Minute
+
+// AsyncOperation is a long running server-side operation started by a call that returned 202 Accepted.
+// ProgressPercent runs from 0 to 100; Message carries the server's explanation when the operation fails.
+type AsyncOperation struct {
+	ID              string `json:"Id"`
+	State           string `json:"State"`
+	ProgressPercent int    `json:"PercentComplete"`
+	Message         string `json:"Message"`
+}
+
+// Done reports whether the operation has finished, successfully or not.
+func (op *AsyncOperation) Done() bool {
+	switch op.State {
+	case OperationSuccess, OperationFailed, OperationCancelled:
+		return true
+	}
+	return false
+}
+
+// AsyncOperationStatus returns the current state of an operation.
+func AsyncOperationStatus(ctx context.Context, opID string) (*AsyncOperation, error) {
+	var op AsyncOperation
+	if err := call(ctx, "GET", fmt.Sprintf("/sf/v3/AsyncOperations(%s)", opID), nil, &op); err != nil {
+		return nil, err
+	}
+
+	return &op, nil
+}
+
+// WaitOption configures WaitForOperation.
+type WaitOption func(*waitOptions)
+
+// Options collected from WaitOption values, internal package use.
+type waitOptions struct {
+	progress func(*AsyncOperation)
+}
+
+// WithOperationProgress calls fn with the operation's state after every poll.
+func WithOperationProgress(fn func(*AsyncOperation)) WaitOption {
+	return func(o *waitOptions) {
+		o.progress = fn
+	}
+}
+
+// WaitForOperation polls an operation until it finishes and returns its final state. Polling starts every poll, or
+// every second when poll isn't positive, and backs off, doubling up to once a minute, while the operation runs; when
+// the API throttles the polling, the wait is stretched to what it asks for. An operation that fails or is cancelled
+// is returned along with an error wrapping ErrOperationFailed and carrying the server's message. It gives up with the
+// context's error when ctx is done.
+func WaitForOperation(ctx context.Context, opID string, poll time.Duration, opts ...WaitOption) (*AsyncOperation, error) {
+	o := &waitOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if poll <= 0 {
+		poll = time.Second
+	}
+
+	for {
+		wait := poll
+
+		op, err := AsyncOperationStatus(ctx, opID)
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+			// Throttled: keep polling, but no sooner than the API asks.
+			if apiErr.RetryAfter > wait {
+				wait = apiErr.RetryAfter
+			}
+		} else if err != nil {
+			return nil, err
+		} else {
+			if o.progress != nil {
+				o.progress(op)
+			}
+			switch op.State {
+			case OperationSuccess:
+				return op, nil
+			case OperationFailed, OperationCancelled:
+				return op, fmt.Errorf("sharefile: operation %s %s: %s: %w", opID, op.State, op.Message, ErrOperationFailed)
+			}
+		}
+
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+
+		if poll < maxOperationPoll {
+			poll *= 2
+			if poll > maxOperationPoll {
+				poll = maxOperationPoll
+			}
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package go-sharefile
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Query builds the OData parameters ($select, $expand, $filter, $orderby, $top, $skip and $inlinecount) accepted by
+// the item and listing calls, along with ShareFile's own listing parameters. Methods modify the query and return it,
+// so calls can be chained:
+//
+//	q := NewQuery().Select("Id", "Name", "Children/Name").Expand("Children").Top(50)
+//
+// A nil *Query adds no parameters.
+type Query struct {
+	selects []string
+	expands []string
+	filter  string
+	orderBy []string
+	top     int
+	hasTop  bool
+	skip    int
+	count   bool
+	deleted bool
+}
+
+// Field presets for Query.Select.
+var (
+	// MinimalFields keeps item responses small when listing large folders.
+	MinimalFields = []string{"Id", "Name", "CreationDate", "FileSizeBytes"}
+	// AllFields returns every field of the item, including those left out by default.
+	AllFields = []string{"*"}
+)
+
+// NewQuery returns an empty query.
+func NewQuery() *Query {
+	return &Query{}
+}
+
+// Select limits the response to the given fields. Fields of expanded relations are given as "Relation/Field". Item
+// fields that aren't selected keep their zero value, and selected fields Item doesn't know are ignored.
+func (q *Query) Select(fields ...string) *Query {
+	q.selects = append(q.selects, fields...)
+	return q
+}
+
+// Expand includes the given relations, such as "Children" or "Parent", in the response.
+func (q *Query) Expand(relations ...string) *Query {
+	q.expands = append(q.expands, relations...)
+	return q
+}
+
+// Filter sets an OData filter expression, for example NameEq("report.pdf") or "Name eq 'report.pdf'".
+func (q *Query) Filter(expr Expr) *Query {
+	q.filter = string(expr)
+	return q
+}
+
+// OrderBy sorts the results by field, descending when desc is true. Repeated calls add further sort keys.
+func (q *Query) OrderBy(field string, desc bool) *Query {
+	if desc {
+		field += " desc"
+	}
+	q.orderBy = append(q.orderBy, field)
+	return q
+}
+
+// Top limits the number of results returned.
+func (q *Query) Top(n int) *Query {
+	q.top = n
+	q.hasTop = true
+	return q
+}
+
+// Skip skips the first n results.
+func (q *Query) Skip(n int) *Query {
+	q.skip = n
+	return q
+}
+
+// InlineCount asks for the total number of results, ignoring $top and $skip, to be included in the response.
+func (q *Query) InlineCount() *Query {
+	q.count = true
+	return q
+}
+
+// IncludeDeleted includes soft-deleted items in listings. Deleted items have IsDeleted set.
+func (q *Query) IncludeDeleted() *Query {
+	q.deleted = true
+	return q
+}
+
+// Returns a copy of the query that can be modified independently, internal package use.
+func (q *Query) clone() *Query {
+	if q == nil {
+		return NewQuery()
+	}
+
+	c := *q
+	c.selects = append([]string(nil), q.selects...)
+	c.expands = append([]string(nil), q.expands...)
+	c.orderBy = append([]string(nil), q.orderBy...)
+	return &c
+}
+
+// Encode renders the query string, without the leading "?". Parameters appear in a fixed order and values are
+// percent-encoded, leaving the characters OData uses in field lists and expressions (such as "/", "," and "'") intact.
+func (q *Query) Encode() string {
+	if q == nil {
+		return ""
+	}
+
+	var params []string
+	add := func(name, value string) {
+		params = append(params, fmt.Sprintf("%s=%s", name, escapeQueryValue(value)))
+	}
+
+	if len(q.selects) > 0 {
+		add("$select", strings.Join(q.selects, ","))
+	}
+	if len(q.expands) > 0 {
+		add("$expand", strings.Join(q.expands, ","))
+	}
+	if q.filter != "" {
+		add("$filter", q.filter)
+	}
+	if len(q.orderBy) > 0 {
+		add("$orderby", strings.Join(q.orderBy, ","))
+	}
+	if q.hasTop {
+		add("$top", strconv.Itoa(q.top))
+	}
+	if q.skip > 0 {
+		add("$skip", strconv.Itoa(q.skip))
+	}
+	if q.count {
+		add("$inlinecount", "allpages")
+	}
+	if q.deleted {
+		add("includeDeleted", "true")
+	}
+
+	return strings.Join(params, "&")
+}
+
+// Appends the query to a request path, internal package use.
+func withQuery(uriPath string, q *Query) string {
+	encoded := q.Encode()
+	if encoded == "" {
+		return uriPath
+	}
+	if strings.Contains(uriPath, "?") {
+		return uriPath + "&" + encoded
+	}
+	return uriPath + "?" + encoded
+}
+
+// Percent-encodes a query value, keeping unreserved characters and the punctuation OData expressions rely on,
+// internal package use.
+func escapeQueryValue(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+			b.WriteByte(c)
+		case strings.IndexByte("-_.~/,'():$*", c) >= 0:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// Quotes a string literal for use in a filter expression, doubling any single quotes, internal package use.
+func quoteODataString(s string) string {
+	return "'" + strings.Replace(s, "'", "''", -1) + "'"
+}
@@ -0,0 +1,80 @@
+package go-sharefile
+
+import "testing"
+
+func TestQueryEncode(t *testing.T) {
+	tests := []struct {
+		name string
+		q    *Query
+		want string
+	}{
+		{"nil", nil, ""},
+		{"empty", NewQuery(), ""},
+		{"select", NewQuery().Select("Id", "Name"), "$select=Id,Name"},
+		{"select relation", NewQuery().Select("Id", "Children/Name"), "$select=Id,Children/Name"},
+		{"expand", NewQuery().Expand("Children", "Parent"), "$expand=Children,Parent"},
+		{"filter", NewQuery().Filter("Name eq 'a b.pdf'"), "$filter=Name%20eq%20'a%20b.pdf'"},
+		{"orderby", NewQuery().OrderBy("Name", false), "$orderby=Name"},
+		{"top zero", NewQuery().Top(0), "$top=0"},
+		{"skip zero", NewQuery().Skip(0), ""},
+		{"top and skip", NewQuery().Top(50).Skip(100), "$top=50&$skip=100"},
+		{"inlinecount", NewQuery().InlineCount(), "$inlinecount=allpages"},
+		{"include deleted", NewQuery().IncludeDeleted(), "includeDeleted=true"},
+		{"all fields", NewQuery().Select(AllFields...), "$select=*"},
+		{
+			"fixed order",
+			NewQuery().IncludeDeleted().InlineCount().Skip(5).Top(10).OrderBy("Name", false).Filter("Name eq 'x'").
+				Expand("Children").Select("Id"),
+			"$select=Id&$expand=Children&$filter=Name%20eq%20'x'&$orderby=Name&$top=10&$skip=5&$inlinecount=allpages&includeDeleted=true",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := tt.q.Encode(); got != tt.want {
+			t.Errorf("%s: Encode() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWithQuery(t *testing.T) {
+	tests := []struct {
+		path string
+		q    *Query
+		want string
+	}{
+		{"/sf/v3/Items(x)", nil, "/sf/v3/Items(x)"},
+		{"/sf/v3/Items(x)", NewQuery().Top(1), "/sf/v3/Items(x)?$top=1"},
+		{"/sf/v3/Items(x)/Children?includeDeleted=true", NewQuery().Top(1), "/sf/v3/Items(x)/Children?includeDeleted=true&$top=1"},
+	}
+
+	for _, tt := range tests {
+		if got := withQuery(tt.path, tt.q); got != tt.want {
+			t.Errorf("withQuery(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestEscapeQueryValue(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"Name", "Name"},
+		{"Id,Children/Name", "Id,Children/Name"},
+		{"startswith(Name,'Q1')", "startswith(Name,'Q1')"},
+		{"datetime'2025-01-02T03:04:05'", "datetime'2025-01-02T03:04:05'"},
+		{"a-b_c.d~e*", "a-b_c.d~e*"},
+		{"a b", "a%20b"},
+		{"a&b=c", "a%26b%3Dc"},
+		{"50%", "50%25"},
+		{"a+b", "a%2Bb"},
+		{"#?", "%23%3F"},
+		{"é", "%C3%A9"},
+	}
+
+	for _, tt := range tests {
+		if got := escapeQueryValue(tt.in); got != tt.want {
+			t.Errorf("escapeQueryValue(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package go-sharefile
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// Defaults for parallel downloads, internal package use.
+const (
+	defaultDownloadParts      = 4
+	parallelDownloadThreshold = 64 << 20
+)
+
+// Downloads a file into f as parallel byte ranges, internal package use. Returns a nil result and error, leaving f
+// untouched, when the item is a folder or too small to be worth splitting.
+func downloadRanges(ctx context.Context, itemID string, f *os.File, o *downloadOptions) (*DownloadResult, error) {
+	item, err := GetItemByID(ctx, itemID, nil)
+	if err != nil {
+		return nil, err
+	}
+	size := item.FileSizeBytes
+	if item.IsFolder() || size < parallelDownloadThreshold {
+		return nil, nil
+	}
+
+	var expected string
+	if o.verify {
+		expected = storedHash(item)
+	}
+
+	spec, err := DownloadURL(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every range runs under parts, so the first to fail for good stops the others, the first range included.
+	parts, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	partSize := (size + int64(o.parts) - 1) / int64(o.parts)
+	first, err := rangeGet(parts, spec, 0, partSize-1)
+	if err != nil {
+		return nil, err
+	}
+	if first.StatusCode != http.StatusPartialContent {
+		defer first.Body.Close()
+		result, err := copyDownload(ctx, itemID, o.progressWriter(f, first.ContentLength), first.Body, expected, o)
+		if result != nil {
+			result.ContentType = first.Header.Get("Content-Type")
+		}
+		return result, err
+	}
+
+	if err := f.Truncate(size); err != nil {
+		first.Body.Close()
+		return nil, err
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		done     int64
+		firstErr error
+	)
+	report := func(n int) {
+		mu.Lock()
+		done += int64(n)
+		if o.progress != nil {
+			o.progress(done, size)
+		}
+		mu.Unlock()
+	}
+
+	contentType := first.Header.Get("Content-Type")
+	resp := first
+	for start := int64(0); start < size; start += partSize {
+		end := start + partSize - 1
+		if end >= size {
+			end = size - 1
+		}
+
+		wg.Add(1)
+		go func(start, end int64, resp *http.Response) {
+			defer wg.Done()
+			if err := fetchRange(parts, spec, f, start, end, resp, report); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				mu.Unlock()
+			}
+		}(start, end, resp)
+		resp = nil
+	}
+	wg.Wait()
+
+	result := &DownloadResult{Bytes: done, ContentType: contentType}
+	if firstErr != nil {
+		return result, downloadError(ctx, itemID, firstErr)
+	}
+	if !o.verify {
+		return result, nil
+	}
+
+	// The parts arrive out of order, so the file is hashed once complete.
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return result, err
+	}
+	sum := md5.New()
+	if _, err := io.Copy(sum, f); err != nil {
+		return result, err
+	}
+	result.Hash = hex.EncodeToString(sum.Sum(nil))
+
+	return result, verifyDownload(itemID, expected, result.Hash)
+}
+
+// Writes one byte range of a download into w, resuming it from where it broke off on failure, internal package use.
+// Failed attempts back off as throttled requests do; errors the host won't get over, such as a refused or missing
+// download, end the range at once. The response to the range's first request may be passed in.
+func fetchRange(ctx context.Context, spec *DownloadSpec, w io.WriterAt, start, end int64, resp *http.Response, report func(int)) error {
+	var (
+		written int64
+		err     error
+	)
+	for attempt := 1; attempt <= maxChunkAttempts; attempt++ {
+		if attempt > 1 {
+			t := time.NewTimer(retryDelay(nil, attempt-1))
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return ctx.Err()
+			case <-t.C:
+			}
+		}
+
+		if resp == nil {
+			resp, err = rangeGet(ctx, spec, start+written, end)
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				if permanentError(err) {
+					return fmt.Errorf("sharefile: bytes %d-%d: %w", start+written, end, err)
+				}
+				continue
+			}
+			if resp.StatusCode != http.StatusPartialContent {
+				resp.Body.Close()
+				return fmt.Errorf("sharefile: bytes %d-%d not served as a range", start+written, end)
+			}
+		}
+
+		out := &offsetWriter{w: w, off: start + written, report: report}
+		n, cerr := io.Copy(out, io.LimitReader(resp.Body, end-start+1-written))
+		resp.Body.Close()
+		resp = nil
+
+		written += n
+		if written == end-start+1 {
+			return nil
+		}
+		err = cerr
+		if err == nil {
+			err = io.ErrUnexpectedEOF
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+
+	return fmt.Errorf("sharefile: bytes %d-%d failed after %d attempts: %w", start, end, maxChunkAttempts, err)
+}
+
+// Reports whether a failed request would fail the same way again, internal package use.
+func permanentError(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode < 500 && apiErr.StatusCode != http.StatusTooManyRequests
+}
+
+// Requests the bytes from..to of a download, internal package use. The response is either 206 Partial Content or,
+// from hosts ignoring ranges, the whole file.
+func rangeGet(ctx context.Context, spec *DownloadSpec, from, to int64) (*http.Response, error) {
+	req, err := downloadRequest(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", from, to))
+
+	resp, err := send(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkResponse(resp); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Writer filling a file from a fixed offset onwards, internal package use.
+type offsetWriter struct {
+	w      io.WriterAt
+	off    int64
+	report func(int)
+}
+
+func (o *offsetWriter) Write(b []byte) (int, error) {
+	n, err := o.w.WriteAt(b, o.off)
+	o.off += int64(n)
+	o.report(n)
+	return n, err
+}
@@ -0,0 +1,108 @@
+package go-sharefile
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// RateLimiter caps the combined speed of the uploads sharing it, with a token bucket holding at most one second's
+// worth of bytes. It is safe for concurrent use.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   int64
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter returns a limiter allowing bytesPerSecond bytes a second, starting with an empty bucket.
+func NewRateLimiter(bytesPerSecond int64) *RateLimiter {
+	return &RateLimiter{
+		rate: bytesPerSecond,
+		last: time.Now(),
+	}
+}
+
+// Takes n bytes from the bucket, waiting until they have accrued or ctx is done, internal package use.
+func (l *RateLimiter) wait(ctx context.Context, n int) error {
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * float64(l.rate)
+	if l.tokens > float64(l.rate) {
+		l.tokens = float64(l.rate)
+	}
+	l.last = now
+	l.tokens -= float64(n)
+
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / float64(l.rate) * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+
+	t := time.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// WithUploadRateLimit caps the upload at bytesPerSecond bytes a second. Each upload gets its own budget; use
+// WithGlobalRateLimit for a budget shared by concurrent uploads.
+func WithUploadRateLimit(bytesPerSecond int64) UploadOption {
+	return func(o *uploadOptions) {
+		if bytesPerSecond > 0 {
+			o.limiters = append(o.limiters, NewRateLimiter(bytesPerSecond))
+		}
+	}
+}
+
+// WithGlobalRateLimit makes the upload draw on a limiter shared with other uploads, so that together they stay within
+// its rate. It can be combined with WithUploadRateLimit, in which case the stricter of the two applies.
+func WithGlobalRateLimit(l *RateLimiter) UploadOption {
+	return func(o *uploadOptions) {
+		if l != nil && l.rate > 0 {
+			o.limiters = append(o.limiters, l)
+		}
+	}
+}
+
+// Wraps r so it is read no faster than the upload's rate limits allow, if any are set, internal package use.
+func (o *uploadOptions) rateLimitedReader(ctx context.Context, r io.Reader) io.Reader {
+	if len(o.limiters) == 0 {
+		return r
+	}
+	return &rateLimitedReader{ctx: ctx, r: r, limiters: o.limiters}
+}
+
+// Reader pacing its reads through one or more rate limiters, internal package use.
+type rateLimitedReader struct {
+	ctx      context.Context
+	r        io.Reader
+	limiters []*RateLimiter
+}
+
+func (l *rateLimitedReader) Read(p []byte) (int, error) {
+	// Reading at most a second's worth at once keeps the transfer steady rather than bursting.
+	for _, lim := range l.limiters {
+		if int64(len(p)) > lim.rate {
+			p = p[:lim.rate]
+		}
+	}
+
+	n, err := l.r.Read(p)
+	for _, lim := range l.limiters {
+		if werr := lim.wait(l.ctx, n); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
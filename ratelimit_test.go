@@ -0,0 +1,52 @@
+package go-sharefile
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestUploadRateLimit(t *testing.T) {
+	if testing.Short() {
+		t.Skip("takes four seconds")
+	}
+	fs := newFakeStorage(t)
+
+	content := strings.Repeat("x", 1<<20)
+	start := time.Now()
+	_, err := Upload(context.Background(), "fo1", "limited.bin", strings.NewReader(content), int64(len(content)),
+		WithUploadRateLimit(256<<10))
+	elapsed := time.Since(start)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if elapsed < 3500*time.Millisecond || elapsed > 5*time.Second {
+		t.Errorf("1 MB at 256 KB/s took %v, want about 4s", elapsed)
+	}
+	if data, _ := fs.file("limited.bin"); len(data) != len(content) {
+		t.Errorf("stored %d bytes", len(data))
+	}
+}
+
+func TestUploadRateLimitCancel(t *testing.T) {
+	newFakeStorage(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	content := strings.Repeat("x", 1<<20)
+	start := time.Now()
+	_, err := Upload(ctx, "fo1", "limited.bin", strings.NewReader(content), int64(len(content)),
+		WithUploadRateLimit(64<<10))
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Upload returned %v, want the context's error", err)
+	}
+	if elapsed > time.Second {
+		t.Errorf("Upload returned %v after its context ended", elapsed-200*time.Millisecond)
+	}
+}
@@ -0,0 +1,76 @@
+package go-sharefile
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Redirection points at the storage zone holding an item. Items in customer-managed zones must be downloaded from
+// and uploaded to the zone's host rather than the account's API host.
+type Redirection struct {
+	// Uri is the item's resource URL on the zone host.
+	Uri string `json:"Uri"`
+	// Root is the ID of the item's root on the zone.
+	Root   string `json:"Root"`
+	Method string `json:"Method"`
+	Domain string `json:"Domain"`
+}
+
+// Hosts of storage zones seen in redirections, which are sent the account's authorization, internal package use.
+var zoneHosts sync.Map
+
+// Redirect policy of the shared HTTP client, internal package use.
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return fmt.Errorf("sharefile: stopped after 10 redirects")
+	}
+	// net/http drops the Authorization header when a redirect changes host, which zone hosts still need.
+	if isZoneHost(req.URL.Host) && req.Header.Get("Authorization") == "" {
+		req.Header.Set("Authorization", getAuthorizationHeader())
+	}
+	return nil
+}
+
+// Reports whether a host is the account's API host or a known storage zone, internal package use.
+func isZoneHost(host string) bool {
+	if host == getHostname() {
+		return true
+	}
+	_, ok := zoneHosts.Load(host)
+	return ok
+}
+
+// ItemRedirection returns the storage zone redirection of an item, or nil when the item is served by the account's
+// API host.
+func ItemRedirection(ctx context.Context, itemID string) (*Redirection, error) {
+	item, err := GetItemByID(ctx, itemID, NewQuery().Select("Id", "Redirection").Expand("Redirection"))
+	if err != nil {
+		return nil, err
+	}
+	if item.Redirection == nil || item.Redirection.Uri == "" {
+		return nil, nil
+	}
+
+	if u, err := url.Parse(item.Redirection.Uri); err == nil {
+		zoneHosts.Store(u.Host, true)
+	}
+
+	return item.Redirection, nil
+}
+
+// Returns the URL of an item resource, such as "/Download", on the host serving the item, internal package use.
+func itemResourceURL(ctx context.Context, itemID, resource string) (string, error) {
+	r, err := ItemRedirection(ctx, itemID)
+	if err != nil {
+		return "", err
+	}
+	if r == nil {
+		return apiURL(fmt.Sprintf("/sf/v3/Items(%s)%s", itemID, resource)), nil
+	}
+
+	return strings.TrimSuffix(r.Uri, "/") + resource, nil
+}
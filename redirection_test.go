@@ -0,0 +1,49 @@
+package go-sharefile
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestCheckRedirectAuthorization(t *testing.T) {
+	fakeAPI(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/zone":
+			http.Redirect(w, r, "https://zone.example.com/sf/v3/Items(fi1)", http.StatusFound)
+		case "/other":
+			http.Redirect(w, r, "https://other.example.com/sf/v3/Items(fi1)", http.StatusFound)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	zoneHosts.Store("zone.example.com", true)
+	t.Cleanup(func() { zoneHosts.Delete("zone.example.com") })
+
+	received := map[string]string{}
+	record := func(host string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received[host] = r.Header.Get("Authorization")
+			writeJSON(t, w, map[string]string{"Id": "fi1"})
+		})
+	}
+	fakeHost(t, "zone.example.com", record("zone"))
+	fakeHost(t, "other.example.com", record("other"))
+
+	ctx := context.Background()
+	var item Item
+	if err := call(ctx, "GET", "/zone", nil, &item); err != nil {
+		t.Fatal(err)
+	}
+	if err := call(ctx, "GET", "/other", nil, &item); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, ok := received["zone"]; !ok || got != "Bearer test-token" {
+		t.Errorf("zone host got Authorization %q, want the account's token", got)
+	}
+	if got, ok := received["other"]; !ok || got != "" {
+		t.Errorf("host outside the zones got Authorization %q, want none", got)
+	}
+}
@@ -0,0 +1,39 @@
+package go-sharefile
+
+import (
+	"context"
+	"fmt"
+)
+
+// RootKind selects one of the well-known root containers.
+type RootKind string
+
+// Aliases of the well-known root containers.
+const (
+	// RootAllShared is the container of every folder shared with the user.
+	RootAllShared RootKind = "allshared"
+	// RootHome is the user's personal "My Files & Folders" folder.
+	RootHome RootKind = "home"
+	// RootTop is the top-level container holding the other roots.
+	RootTop RootKind = "top"
+	// RootFavorites is the container of the user's favorite folders.
+	RootFavorites RootKind = "favorites"
+	// RootConnectors is the container of connector roots, such as network shares.
+	RootConnectors RootKind = "connectors"
+)
+
+// Root returns one of the well-known root containers, including its children when expandChildren is true. Client
+// users can't access some of the roots and get an error wrapping ErrForbidden.
+func Root(ctx context.Context, kind RootKind, expandChildren bool) (*Item, error) {
+	var q *Query
+	if expandChildren {
+		q = NewQuery().Expand("Children")
+	}
+
+	item, err := GetItemByID(ctx, string(kind), q)
+	if err != nil {
+		return nil, fmt.Errorf("sharefile: root %s: %w", kind, err)
+	}
+
+	return item, nil
+}
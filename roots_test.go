@@ -0,0 +1,43 @@
+package go-sharefile
+
+import (
+	"net/http"
+	"testing"
+)
+
+// GetRoot used to take a variadic bool and panic without one. RootOptions makes the zero value the no-argument case,
+// and extra arguments no longer compile.
+func TestGetRootOptions(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       RootOptions
+		wantExpand string
+	}{
+		{"zero value", RootOptions{}, ""},
+		{"without children", RootOptions{ExpandChildren: false}, ""},
+		{"with children", RootOptions{ExpandChildren: true}, "Children"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var path, expand string
+			fakeAPI(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path, expand = r.URL.Path, r.URL.Query().Get("$expand")
+				writeJSON(t, w, map[string]interface{}{
+					"Id":       "allshared",
+					"Name":     "Shared Folders",
+					"Children": []map[string]string{{"Id": "fo1", "Name": "Clients"}},
+				})
+			}))
+
+			GetRoot(tt.opts)
+
+			if path != "/sf/v3/Items(allshared)" {
+				t.Errorf("requested %s", path)
+			}
+			if expand != tt.wantExpand {
+				t.Errorf("$expand = %q, want %q", expand, tt.wantExpand)
+			}
+		})
+	}
+}
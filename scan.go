@@ -0,0 +1,65 @@
+package go-sharefile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInfected is returned by WaitForScan when the virus scanner flags a file.
+var ErrInfected = errors.New("sharefile: file is infected")
+
+// ScanResult is the virus scan status of a file.
+type ScanResult string
+
+// Virus scan statuses reported on files.
+const (
+	ScanNotScanned ScanResult = "NotScanned"
+	ScanPending    ScanResult = "Pending"
+	ScanClean      ScanResult = "Clean"
+	ScanInfected   ScanResult = "Infected"
+)
+
+// Poll interval used by the waiting and watching calls when they aren't given a positive one, internal package use.
+const defaultPollInterval = time.Second
+
+// WaitForScan polls a file every pollInterval, or every second when pollInterval isn't positive, until the virus scan
+// completes, returning ScanClean, or ScanInfected along with an error wrapping ErrInfected. It gives up with the
+// context's error when ctx is done. When the API throttles the polling, the wait is stretched to what it asks for.
+func WaitForScan(ctx context.Context, itemID string, pollInterval time.Duration) (ScanResult, error) {
+	if pollInterval <= 0 {
+		pollInterval = defaultPollInterval
+	}
+	q := NewQuery().Select("Id", "VirusStatus")
+
+	for {
+		wait := pollInterval
+
+		item, err := GetItemByID(ctx, itemID, q)
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+			// Throttled: keep polling, but no sooner than the API asks.
+			if apiErr.RetryAfter > wait {
+				wait = apiErr.RetryAfter
+			}
+		} else if err != nil {
+			return "", err
+		} else {
+			switch item.VirusStatus {
+			case ScanClean:
+				return ScanClean, nil
+			case ScanInfected:
+				return ScanInfected, fmt.Errorf("sharefile: item %s: %w", itemID, ErrInfected)
+			}
+		}
+
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return "", ctx.Err()
+		case <-t.C:
+		}
+	}
+}
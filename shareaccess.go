@@ -0,0 +1,227 @@
+package go-sharefile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Errors returned by the recipient side share calls.
+var (
+	ErrShareExpired     = errors.New("sharefile: share has expired")
+	ErrShareUploadLimit = errors.New("sharefile: share accepts no more files")
+)
+
+// ShareLoginRequiredError is returned by the recipient side share calls for shares that only signed in users may
+// open. Call Authenticate with the recipient's credentials and try again.
+type ShareLoginRequiredError struct {
+	ShareID string
+}
+
+func (e *ShareLoginRequiredError) Error() string {
+	return fmt.Sprintf("sharefile: share %s requires signing in", e.ShareID)
+}
+
+// SetSubdomain sets the account the package talks to without authenticating, for opening shares as an anonymous
+// recipient. The subdomain is the first label of the share link's host, such as "acme" for acme.sharefile.com.
+func SetSubdomain(subdomain string) {
+	if token == nil {
+		token = map[string]string{}
+	}
+	token["subdomain"] = subdomain
+}
+
+// Reports whether the package holds an access token, internal package use.
+func authenticated() bool {
+	return token["access_token"] != ""
+}
+
+// Builds and sends a request against a share, without authorization when the package isn't authenticated, internal
+// package use.
+func callShare(ctx context.Context, method, uriPath string, body, out interface{}) error {
+	req, err := newRequest(ctx, method, uriPath, body)
+	if err != nil {
+		return err
+	}
+	if !authenticated() {
+		req.Header.Del("Authorization")
+	}
+
+	return do(req, out)
+}
+
+// Returns the alias a recipient opens a share as, internal package use. An email address is registered with the
+// share first, which is the handshake shares requiring recipient information ask for; anything else is taken to be
+// an alias ID already.
+func shareAlias(ctx context.Context, shareID, aliasOrEmail string) (string, error) {
+	var share Share
+	uriPath := withQuery(fmt.Sprintf("/sf/v3/Shares(%s)", shareID), NewQuery().Select("Id", "ExpirationDate", "RequireLogin", "RequireUserInfo"))
+	err := callShare(ctx, "GET", uriPath, nil, &share)
+	if errors.Is(err, ErrUnauthorized) || (err == nil && share.RequireLogin && !authenticated()) {
+		return "", &ShareLoginRequiredError{ShareID: shareID}
+	}
+	if err != nil {
+		return "", err
+	}
+	if share.IsExpired() {
+		return "", fmt.Errorf("sharefile: share %s expired %s: %w", shareID, share.ExpirationDate.Format(time.RFC3339), ErrShareExpired)
+	}
+
+	if !strings.Contains(aliasOrEmail, "@") {
+		if aliasOrEmail == "" && share.RequireUserInfo {
+			return "", fmt.Errorf("sharefile: share %s asks for the recipient's email address", shareID)
+		}
+		return aliasOrEmail, nil
+	}
+
+	params := url.Values{}
+	params.Set("Email", aliasOrEmail)
+
+	var alias ShareAlias
+	if err := callShare(ctx, "POST", fmt.Sprintf("/sf/v3/Shares(%s)/Recipients?%s", shareID, params.Encode()), nil, &alias); err != nil {
+		return "", err
+	}
+	if alias.ID == "" {
+		return "", fmt.Errorf("sharefile: no alias received for %s on share %s", aliasOrEmail, shareID)
+	}
+
+	return alias.ID, nil
+}
+
+// Returns the path of a resource under a share, opened as the given alias, internal package use.
+func sharePath(shareID, alias, resource string, params url.Values) string {
+	if params == nil {
+		params = url.Values{}
+	}
+	if alias != "" {
+		params.Set("alias", alias)
+	}
+
+	uriPath := fmt.Sprintf("/sf/v3/Shares(%s)%s", shareID, resource)
+	if len(params) > 0 {
+		uriPath += "?" + params.Encode()
+	}
+	return uriPath
+}
+
+// ShareItems returns the items in a share as seen by a recipient, who needn't have an account. alias is an alias ID
+// or the recipient's email address, which shares asking for recipient information need; it may be empty for others.
+// Without Authenticate, set the account with SetSubdomain first.
+func ShareItems(ctx context.Context, shareID, alias string) ([]Item, error) {
+	aliasID, err := shareAlias(ctx, shareID, alias)
+	if err != nil {
+		return nil, err
+	}
+
+	var feed itemFeed
+	if err := callShare(ctx, "GET", sharePath(shareID, aliasID, "/Items", nil), nil, &feed); err != nil {
+		return nil, err
+	}
+
+	return feed.Items, nil
+}
+
+// ShareDownload downloads an item of a share to w as a recipient, who needn't have an account. aliasOrEmail is as for
+// ShareItems. Shares only signed in users may open fail with a *ShareLoginRequiredError.
+func ShareDownload(ctx context.Context, shareID, aliasOrEmail string, itemID string, w io.Writer) error {
+	aliasID, err := shareAlias(ctx, shareID, aliasOrEmail)
+	if err != nil {
+		return err
+	}
+
+	params := url.Values{}
+	params.Set("redirect", "false")
+
+	var spec DownloadSpec
+	uriPath := sharePath(shareID, aliasID, fmt.Sprintf("/Items(%s)/Download", itemID), params)
+	if err := callShare(ctx, "GET", uriPath, nil, &spec); err != nil {
+		return err
+	}
+	if spec.URL == "" {
+		return fmt.Errorf("sharefile: no download URL received for item %s of share %s", itemID, shareID)
+	}
+
+	req, err := http.NewRequest("GET", spec.URL, nil)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+
+	resp, err := send(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := checkResponse(resp); err != nil {
+		return err
+	}
+
+	_, err = copyDownload(ctx, itemID, w, resp.Body, "", newDownloadOptions(nil))
+	return err
+}
+
+// ShareUpload uploads the contents of r as a file called name into a Request share as a recipient, who needn't have
+// an account. aliasOrEmail is as for ShareItems, and size and the options as for Upload, whose streaming and chunking
+// it shares; WithChecksum has no effect, as recipients can't read back the stored file. A share past its expiration
+// fails with an error wrapping ErrShareExpired, and one that has taken as many files as it allows with an error
+// wrapping ErrShareUploadLimit.
+func ShareUpload(ctx context.Context, shareID, aliasOrEmail string, name string, r io.Reader, size int64, opts ...UploadOption) error {
+	aliasID, err := shareAlias(ctx, shareID, aliasOrEmail)
+	if err != nil {
+		return err
+	}
+
+	o := newUploadOptions(opts)
+	o.checksum = false
+
+	_, _, err = transferUpload(ctx, name, r, size, o, func() (*uploadSpec, error) {
+		return requestShareUploadSpec(ctx, shareID, aliasID, name, size, o)
+	})
+
+	return shareUploadError(shareID, err)
+}
+
+// Requests the upload specification for a file sent to a Request share, internal package use.
+func requestShareUploadSpec(ctx context.Context, shareID, aliasID, name string, size int64, o *uploadOptions) (*uploadSpec, error) {
+	params := o.specParams()
+	params.Set("fileName", name)
+	params.Set("responseFormat", "json")
+	if size >= 0 {
+		params.Set("fileSize", strconv.FormatInt(size, 10))
+	}
+
+	var spec uploadSpec
+	if err := callShare(ctx, "GET", sharePath(shareID, aliasID, "/Upload", params), nil, &spec); err != nil {
+		return nil, err
+	}
+	if spec.ChunkURI == "" {
+		return nil, fmt.Errorf("sharefile: no upload URL received for share %s", shareID)
+	}
+
+	return &spec, nil
+}
+
+// Tells an upload refused because the share expired or is full apart from other failures, internal package use. The
+// API only says which in its message.
+func shareUploadError(shareID string, err error) error {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+
+	msg := strings.ToLower(apiErr.Message)
+	switch {
+	case apiErr.StatusCode == http.StatusGone || strings.Contains(msg, "expired"):
+		return fmt.Errorf("sharefile: upload to share %s refused (%v): %w", shareID, err, ErrShareExpired)
+	case strings.Contains(msg, "limit") || strings.Contains(msg, "maximum"):
+		return fmt.Errorf("sharefile: upload to share %s refused (%v): %w", shareID, err, ErrShareUploadLimit)
+	}
+	return err
+}
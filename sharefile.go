@@ -2,12 +2,17 @@ package go-sharefile
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
+	"mime"
+	"mime/multipart"
 	"net/http"
+	"net/textproto"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -17,8 +22,7 @@ import (
 
 var (
 	// token used for accessing auth data once initialised,  without needing to call a function
-	token        map[string]string
-	uploadConfig map[string]string
+	token map[string]string
 )
 
 // Generic struct for the top level json object response, contains additional childObject struct as an array for an arbitrary number of children.
@@ -37,8 +41,9 @@ type childObject struct {
 
 // Struct for use in folder POST activities
 type folderBody struct {
-	Name        string
-	Description string
+	Name           string
+	Description    string
+	ExpirationDate *time.Time `json:",omitempty"`
 }
 
 // Struct for use in user POST activities
@@ -47,23 +52,30 @@ type userBody struct {
 	FirstName         string
 	LastName          string
 	Company           string
-	ClientPassword    string
+	ClientPassword    string `json:",omitempty"`
 	CanResetPassword  bool
 	CanViewMySettings bool
 }
 
-// Struct for use in client POST/GET activities
-type valueBody struct {
-	Client []clientBody `json:"value"`
-}
-
-type clientBody struct {
-	ID    string `json:"Id"`
-	Email string `json:"Email"`
-}
-
 // Authenticate authenticates against the given instance, and should be the first function to be run, as it prepares auth for the entire package.
+// Failures are reported through Logger; use Login to handle them.
 func Authenticate(hostname, clientID, clientSecret, username, password string) {
+	if err := Login(context.Background(), hostname, clientID, clientSecret, username, password); err != nil {
+		Logger.Printf("authentication failed: %v", err)
+	}
+}
+
+// Login authenticates against the given instance with a username and password, as Authenticate does, and returns
+// why it failed. The account's single sign-on configuration is checked first, so an account that only allows signing
+// in through its identity provider fails with an error wrapping ErrWebAuthRequired rather than a refused password.
+func Login(ctx context.Context, hostname, clientID, clientSecret, username, password string) error {
+	if subdomain := accountSubdomain(hostname); subdomain != "" {
+		// The check is best effort; without an answer the password grant is tried anyway.
+		info, err := GetSSOInfo(ctx, subdomain)
+		if err == nil && !info.PasswordLoginAllowed() {
+			return fmt.Errorf("sharefile: account %s signs in through %s: %w", subdomain, info.LoginURL, ErrWebAuthRequired)
+		}
+	}
 
 	uriPath := "/oauth/token"
 
@@ -75,18 +87,55 @@ func Authenticate(hostname, clientID, clientSecret, username, password string) {
 		"password":      {password},
 	}
 
-	resp, err := http.PostForm(fmt.Sprintf("%s%s", hostname, uriPath), message)
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s%s", hostname, uriPath), strings.NewReader(message.Encode()))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := send(req)
 	if err != nil {
-		return
+		return err
 	}
 	defer resp.Body.Close()
 
-	var tokenResponse map[string]string
+	if err := checkResponse(resp); err != nil {
+		return err
+	}
+
+	// Besides the token strings, the response carries numbers such as expires_in.
+	var tokenResponse map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResponse); err != nil {
+		return err
+	}
+
+	t := map[string]string{}
+	for k, v := range tokenResponse {
+		t[k] = fmt.Sprint(v)
+	}
+	if t["access_token"] == "" {
+		return fmt.Errorf("sharefile: no access token received from %s", hostname)
+	}
+	token = t
+	resetCapabilities()
 
-	json.NewDecoder(resp.Body).Decode(&tokenResponse)
+	return nil
+}
 
-	token = tokenResponse
+// Returns the account subdomain of an instance's address, such as "acme" for https://acme.sharefile.com, or an
+// empty string when it has none, internal package use.
+func accountSubdomain(hostname string) string {
+	u, err := url.Parse(hostname)
+	if err != nil || u.Hostname() == "" {
+		return ""
+	}
 
+	labels := strings.Split(u.Hostname(), ".")
+	if len(labels) < 3 {
+		return ""
+	}
+	return labels[0]
 }
 
 // Returns ShareFile authorization header, internal package use.
@@ -99,39 +148,18 @@ func getHostname() string {
 	return fmt.Sprintf("%s.sf-api.com", token["subdomain"])
 }
 
-// GetRoot returns the root level Item for the provided user.
-func GetRoot(getChildren ...bool) {
-	uriPath := "/sf/v3/Items(allshared)"
-	if getChildren[0] {
-		uriPath = fmt.Sprintf("%s?$expand=Children", uriPath)
-	}
-
-	client := http.Client{}
-
-	req, err := http.NewRequest("GET", fmt.Sprintf("https://%s%s", getHostname(), uriPath), nil)
-	if err != nil {
-		log.Fatalln(err)
-	}
-
-	req.Header.Add("Authorization", getAuthorizationHeader())
+// RootOptions configures GetRoot. The zero value leaves out the root's children.
+type RootOptions struct {
+	// ExpandChildren includes the children of the root.
+	ExpandChildren bool
+}
 
-	resp, err := client.Do(req)
+// GetRoot returns the root level Item for the provided user.
+func GetRoot(opts RootOptions) {
+	items, err := Root(context.Background(), RootAllShared, opts.ExpandChildren)
 	if err != nil {
 		log.Fatalln(err)
 	}
-	defer resp.Body.Close()
-
-	// Load Json into structs
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	items := baseObject{}
-	err = json.Unmarshal(body, &items)
-	if err != nil {
-		log.Fatal(err)
-	}
 
 	fmt.Printf("%s %s %s\n", items.ID, items.CreationDate, items.Name)
 	if len(items.Children) != 0 {
@@ -142,36 +170,16 @@ func GetRoot(getChildren ...bool) {
 	}
 }
 
-// GetItemByID returns a single item, for which the ID is provided.
-func GetItemByID(itemID string) {
-	uriPath := fmt.Sprintf("/sf/v3/Items(%s)", itemID)
-
-	client := http.Client{}
-	req, err := http.NewRequest("GET", fmt.Sprintf("https://%s%s", getHostname(), uriPath), nil)
-	if err != nil {
-		log.Fatalln(err)
-	}
+// GetItemByID returns a single item, for which the ID is provided. The query may be nil.
+func GetItemByID(ctx context.Context, itemID string, q *Query) (*Item, error) {
+	uriPath := withQuery(fmt.Sprintf("/sf/v3/Items(%s)", itemID), q)
 
-	req.Header.Add("Authorization", getAuthorizationHeader())
-
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Fatalln(err)
+	var item Item
+	if err := call(ctx, "GET", uriPath, nil, &item); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	items := baseObject{}
-	err = json.Unmarshal(body, &items)
-	if err != nil {
-		log.Fatalln(err)
-	}
-
-	fmt.Printf("%v %v %v\n", items.ID, items.CreationDate, items.Name)
+	return &item, nil
 }
 
 // GetFolderWithQueryParameters gets a folder using some of the common query parameters that are available.
@@ -181,7 +189,10 @@ func GetItemByID(itemID string) {
 // select=Id,Name,Children/Id,Children/Name,Children/CreationDate to get the Id, Name of the folder
 // and the Id, Name, CreationDae of any Children
 func GetFolderWithQueryParameters(itemID string) {
-	uriPath := fmt.Sprintf("/sf/v3/Items(%s)?$expand=Children&$select=Id,Name,Children/Id,Children/Name,Children/CreationDate", itemID)
+	q := NewQuery().
+		Expand("Children").
+		Select("Id", "Name", "Children/Id", "Children/Name", "Children/CreationDate")
+	uriPath := withQuery(fmt.Sprintf("/sf/v3/Items(%s)", itemID), q)
 	fmt.Printf("GET %s%s", getHostname(), uriPath)
 	client := http.Client{}
 
@@ -219,91 +230,51 @@ func GetFolderWithQueryParameters(itemID string) {
 	}
 }
 
-// CreateFolder creates a new folder in the given parent folder.
-func CreateFolder(parentID string, name string, description string) {
-	folder := folderBody{
-		Name:        name,
-		Description: description,
-	}
-
-	f, err := json.Marshal(folder)
-	if err != nil {
-		log.Fatalln(err)
-	}
-
-	var dataBytes bytes.Buffer
-	dataBytes.Write(f)
-
-	uriPath := fmt.Sprintf("/sf/v3/Items(%s)/Folder", parentID)
-	fmt.Printf("POST %s%s", getHostname(), uriPath)
-	client := http.Client{}
-
-	req, err := http.NewRequest("POST", fmt.Sprintf("https://%s%s", getHostname(), uriPath), bytes.NewBuffer(dataBytes.Bytes()))
-	if err != nil {
-		log.Fatalln(err)
-	}
-
-	req.Header.Add("Authorization", getAuthorizationHeader())
-	req.Header.Add("Content-Type", "application/json")
-
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Fatalln(err)
-	}
-	defer resp.Body.Close()
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		log.Fatalln(err)
-	}
-
-	fmt.Println(resp.Status)
-
-	items := baseObject{}
-	err = json.Unmarshal(body, &items)
-	if err != nil {
-		log.Fatalln(err)
-	}
-
-	fmt.Printf("Created folder %v", items.ID)
-
-}
-
-// UpdateItem updates the name and description of an item.
-func UpdateItem(itemID string, name string, description string) {
+// CreateFolder creates a new folder in the given parent folder and returns it, with Name as actually stored and its
+// Parent. By default a folder with the same name already in the parent is an error wrapping ErrConflict; see
+// WithFolderConflict and WithPassthrough for the alternatives.
+func CreateFolder(ctx context.Context, parentID string, name string, description string, opts ...FolderOption) (*Item, error) {
+	o := newFolderOptions(opts)
 
 	folder := folderBody{
-		Name:        name,
-		Description: description,
-	}
-
-	f, err := json.Marshal(folder)
-	if err != nil {
-		log.Fatalln(err)
+		Name:           name,
+		Description:    description,
+		ExpirationDate: o.expiration,
 	}
 
-	var dataBytes bytes.Buffer
-	dataBytes.Write(f)
+	uriPath := fmt.Sprintf("/sf/v3/Items(%s)/Folder?%s", parentID, o.params().Encode())
+	uriPath = withQuery(uriPath, NewQuery().Expand("Parent"))
 
-	uriPath := fmt.Sprintf("/sf/v3/Items(%s)/Folder", itemID)
-	fmt.Printf("PATCH %s%s", getHostname(), uriPath)
-	client := http.Client{}
+	for n := 2; ; n++ {
+		var item Item
+		err := call(ctx, "POST", uriPath, folder, &item)
+		if err == nil {
+			return &item, nil
+		}
+		if !o.rename || !errors.Is(err, ErrConflict) || n > maxRenameAttempts {
+			return nil, err
+		}
 
-	req, err := http.NewRequest("PATCH", fmt.Sprintf("https://%s%s", getHostname(), uriPath), bytes.NewBuffer(dataBytes.Bytes()))
-	if err != nil {
-		log.Fatalln(err)
+		folder.Name = numberedName(name, n)
 	}
+}
 
-	req.Header.Add("Authorization", getAuthorizationHeader())
-	req.Header.Add("Content-Type", "application/json")
+// ItemUpdate holds the fields UpdateItem changes. Nil fields are left as they are.
+type ItemUpdate struct {
+	Name        *string `json:",omitempty"`
+	Description *string `json:",omitempty"`
+	// ExpirationDate sets when the item expires. Point it at NeverExpires to clear an expiration.
+	ExpirationDate *time.Time `json:",omitempty"`
+}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Fatalln(err)
+// UpdateItem updates the name, description or expiration of an item and returns the updated item.
+func UpdateItem(ctx context.Context, itemID string, update ItemUpdate) (*Item, error) {
+	var item Item
+	if err := call(ctx, "PATCH", fmt.Sprintf("/sf/v3/Items(%s)", itemID), update, &item); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	fmt.Println(resp.Status)
+	return &item, nil
 }
 
 // DeleteItem deletes and item by id.
@@ -331,223 +302,262 @@ func DeleteItem(itemID string) {
 
 }
 
-// DownloadItem downloads a single item. If downloading a folder the localPath name should end in .zip.
-func DownloadItem(itemID string, localPath string) {
-	uriPath := fmt.Sprintf("/sf/v3/Items(%s)/Download", itemID)
-	fmt.Printf("GET %s%s\n", getHostname(), uriPath)
-	client := http.Client{}
+// DownloadItem downloads a single item to a local file with Download. If downloading a folder the localPath name should
+// end in .zip.
+//
+// The item is written to a temporary file next to localPath, which is renamed into place once complete, so a failed
+// or cancelled download leaves neither a partial file nor a changed localPath behind.
+func DownloadItem(ctx context.Context, itemID string, localPath string, opts ...DownloadOption) (*DownloadResult, error) {
+	if newDownloadOptions(opts).skipCurrent {
+		item, err := GetItemByID(ctx, itemID, nil)
+		if err != nil {
+			return nil, err
+		}
+		current, err := localCurrent(localPath, item)
+		if err != nil {
+			return nil, err
+		}
+		if current {
+			return &DownloadResult{Hash: item.Hash, Skipped: true}, nil
+		}
+	}
 
-	req, err := http.NewRequest("GET", fmt.Sprintf("https://%s%s", getHostname(), uriPath), nil)
+	out, err := os.CreateTemp(filepath.Dir(localPath), filepath.Base(localPath)+".*.part")
 	if err != nil {
-		log.Fatalln(err)
+		return nil, err
 	}
 
-	req.Header.Add("Authorization", getAuthorizationHeader())
-
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Fatalln(err)
+	var result *DownloadResult
+	if o := newDownloadOptions(opts); o.parts > 0 && !o.allVersions && !o.pdf {
+		result, err = downloadRanges(ctx, itemID, out, o)
+	}
+	if result == nil && err == nil {
+		result, err = Download(ctx, itemID, out, opts...)
 	}
-	defer resp.Body.Close()
 
-	out, err := os.Create(localPath)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(out.Name(), localPath)
+	}
 	if err != nil {
-		log.Fatalln(err)
+		os.Remove(out.Name())
+		return result, err
 	}
-	defer out.Close()
 
-	if _, err := io.Copy(out, resp.Body); err != nil {
-		log.Fatalln(err)
-	}
+	return result, nil
 }
 
-// UploadFile uploads a File using the standard upload method with a multipart/form mime encoded POST
-func UploadFile(localPath string, folderID string) int {
-	if token["access_token"] == "" {
-		log.Println("ShareFile token not obtained")
+// UploadFile uploads a File using the standard upload method with a multipart/form mime encoded POST, and returns the
+// uploaded file. A file with the same name already in the folder is handled as WithUploadConflict describes.
+func UploadFile(ctx context.Context, localPath string, folderID string, opts ...UploadOption) (*Item, error) {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return nil, err
 	}
+	defer file.Close()
 
-	client := http.Client{}
-	uriPath := fmt.Sprintf("/sf/v3/Items(%s)/Upload", folderID)
-
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s%s", getHostname(), uriPath), nil)
+	info, err := file.Stat()
 	if err != nil {
-		log.Fatalln(err)
+		return nil, err
 	}
 
-	req.Header.Add("Authorization", getAuthorizationHeader())
-
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Fatalln(err)
+	name := filepath.Base(localPath)
+	o := newUploadOptions(opts)
+	if o.remoteName != "" {
+		name = o.remoteName
 	}
-	defer resp.Body.Close()
 
-	json.NewDecoder(resp.Body).Decode(&uploadConfig)
+	defaults := []UploadOption{WithClientTimes(time.Time{}, info.ModTime())}
 
-	var uploadResponse *http.Response
-	if len(uploadConfig["ChunkUri"]) > 0 {
-		uploadResponse = multipartFormPostUpload(uploadConfig["ChunkUri"], localPath)
-	} else {
-		log.Print("No Upload URL received")
+	if o.skipUnchanged {
+		existing, unchanged, local, err := unchangedRemote(ctx, file, info, folderID, name, o)
+		if err != nil {
+			return nil, err
+		}
+		if unchanged {
+			existing.Skipped = true
+			return existing, nil
+		}
+		if existing != nil {
+			defaults = append(defaults, WithUploadConflict(ConflictOverwrite))
+		}
+		if local != "" {
+			opts = append(opts, withLocalHash(local))
+		}
 	}
 
-	return uploadResponse.StatusCode
+	// The defaults go first so the caller's own WithClientTimes or WithUploadConflict replaces them.
+	opts = append(defaults, opts...)
 
+	return Upload(ctx, folderID, name, file, info.Size(), opts...)
 }
 
-// Does a multipart form post upload of a file to a url, internal package use.
-func multipartFormPostUpload(u string, fp string) *http.Response {
-	filename := filepath.Base(fp)
-	data := strings.Builder{}
-	headers := make(map[string]string)
+// Does a multipart form post upload to a url, internal package use. The reader is streamed into the request body as
+// it is sent, so memory use doesn't grow with the size of the upload. When size is known the request carries the
+// exact Content-Length; otherwise it is sent with chunked transfer encoding. Returns the response body.
+func multipartFormPostUpload(ctx context.Context, u string, name string, contentType string, r io.Reader, size int64) ([]byte, error) {
+	var framing bytes.Buffer
+	mw := multipart.NewWriter(&framing)
 
-	fileContent, err := getContentType(fp)
-	if err != nil {
-		fileContent = "application/octet-stream"
-	}
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", formDataDisposition("File1", name))
+	header.Set("Content-Type", contentType)
 
-	file, err := ioutil.ReadFile(fp)
-	if err != nil {
-		log.Fatalln(err)
+	if _, err := mw.CreatePart(header); err != nil {
+		return nil, err
 	}
+	head := append([]byte(nil), framing.Bytes()...)
 
-	boundary := fmt.Sprintf("----------%v", time.Now().Unix())
-	headers["Content-Type"] = fmt.Sprintf("multipart/form-data; boundary=%v", boundary)
-
-	// To note if copying/converting this data structure, carriage returns are required. Interpret it exactly as written.
-	data.WriteString(fmt.Sprintf("--%v\r\n", boundary))
-	data.WriteString(fmt.Sprintf("Content-Disposition: form-data; name=\"File1\"; filename=\"%v\"\r\n", filename))
-	data.WriteString(fmt.Sprintf("Content-Type: %v\r\n\r\n", fileContent))
-	data.Write(file)
-	data.WriteString(fmt.Sprintf("\r\n--%v--\r\n", boundary))
-
-	var dataStr bytes.Buffer
+	framing.Reset()
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	tail := framing.Bytes()
 
-	dataStr.WriteString(data.String())
+	uri, err := url.Parse(u)
+	if err != nil {
+		return nil, err
+	}
 
-	uri, _ := url.Parse(u)
+	var body io.Reader
+	if size >= 0 {
+		body = io.MultiReader(bytes.NewReader(head), io.LimitReader(r, size), bytes.NewReader(tail))
+	} else {
+		body = io.MultiReader(bytes.NewReader(head), r, bytes.NewReader(tail))
+	}
 
-	req, err := http.NewRequest("POST", uri.String(), bytes.NewBuffer(dataStr.Bytes()))
+	req, err := http.NewRequest("POST", uri.String(), body)
 	if err != nil {
-		log.Fatalln("Request failed")
+		return nil, err
 	}
+	req = req.WithContext(ctx)
 
-	headers["Content-Length"] = string(dataStr.Len())
-
-	for hdrName, hdrValue := range headers {
-		req.Header.Add(hdrName, string(hdrValue))
+	// Content-Length set as a header is ignored by net/http; the length has to go on the request itself.
+	if size >= 0 {
+		req.ContentLength = int64(len(head)) + size + int64(len(tail))
+	} else {
+		req.ContentLength = -1
 	}
 
-	client := &http.Client{}
+	req.Header.Add("Content-Type", mw.FormDataContentType())
 
-	resp, err := client.Do(req)
+	resp, err := httpClient.Do(req)
 	if err != nil {
-		log.Fatalln(err)
+		return nil, err
 	}
 	defer resp.Body.Close()
 
-	return resp
-}
-
-func getContentType(f string) (string, error) {
-
-	buffer := make([]byte, 512)
-
-	buffer, err := ioutil.ReadFile(f)
-	if err != nil {
-		return "", err
+	if err := checkResponse(resp); err != nil {
+		return nil, err
 	}
 
-	contentType := http.DetectContentType(buffer)
-	return contentType, nil
+	return ioutil.ReadAll(resp.Body)
 }
 
-// GetClients gets the client users in the account.
-func GetClients() {
-	uriPath := "/sf/v3/Accounts/Clients"
-	fmt.Printf("GET %s%s\n", getHostname(), uriPath)
-	client := http.Client{}
-
-	req, err := http.NewRequest("GET", fmt.Sprintf("https://%v%v", getHostname(), uriPath), nil)
-	if err != nil {
-		log.Fatalln(err)
+// Escapes quotes and backslashes in quoted header parameters, internal package use.
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// Returns the Content-Disposition of a form-data file part, internal package use. Names outside printable ASCII are
+// also given as an RFC 5987 filename* parameter, with an ASCII approximation in filename for older parsers.
+func formDataDisposition(field, filename string) string {
+	ascii := true
+	for i := 0; i < len(filename); i++ {
+		if filename[i] < 0x20 || filename[i] > 0x7e {
+			ascii = false
+			break
+		}
 	}
-
-	req.Header.Add("Authorization", getAuthorizationHeader())
-
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Fatalln(err)
+	if ascii {
+		return fmt.Sprintf(`form-data; name="%s"; filename="%s"`, field, quoteEscaper.Replace(filename))
 	}
-	defer resp.Body.Close()
 
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		log.Fatalln(err)
-	}
+	fallback := strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e {
+			return '_'
+		}
+		return r
+	}, filename)
 
-	items := valueBody{}
-	err = json.Unmarshal(body, &items)
-	if err != nil {
-		log.Fatalln(err)
-	}
+	return fmt.Sprintf(`form-data; name="%s"; filename="%s"; filename*=UTF-8''%s`,
+		field, quoteEscaper.Replace(fallback), rfc5987Escape(filename))
+}
 
-	for i := range items.Client {
-		fmt.Printf("%s %s\n", items.Client[i].ID, items.Client[i].Email)
+// Percent-encodes a value as an RFC 5987 ext-value, internal package use.
+func rfc5987Escape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+			b.WriteByte(c)
+		case strings.IndexByte("!#$&+-.^_`|~", c) >= 0:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
 	}
+	return b.String()
 }
 
-// CreateClient creates a client user in the account
-func CreateClient(email, firstname, lastname, company, clientpassword string, canresetpassword, canviewmysettings bool) {
-	user := userBody{
-		Email:             email,
-		FirstName:         firstname,
-		LastName:          lastname,
-		Company:           company,
-		ClientPassword:    clientpassword,
-		CanResetPassword:  canresetpassword,
-		CanViewMySettings: canviewmysettings,
+// Returns the content type of a local file, from its extension when it has a known one, otherwise sniffed from the
+// start of the file, internal package use.
+func getContentType(f string) (string, error) {
+	if ct := mime.TypeByExtension(filepath.Ext(f)); ct != "" {
+		return ct, nil
 	}
 
-	u, err := json.Marshal(user)
+	file, err := os.Open(f)
 	if err != nil {
-		log.Fatalln(err)
+		return "", err
 	}
+	defer file.Close()
 
-	var dataBytes bytes.Buffer
-	dataBytes.Write(u)
+	buffer := make([]byte, sniffLen)
+	n, err := io.ReadFull(file, buffer)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
 
-	uriPath := "/sf/v3/Users"
-	fmt.Printf("POST %s%s", getHostname(), uriPath)
-	client := http.Client{}
+	return http.DetectContentType(buffer[:n]), nil
+}
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("https://%s%s", getHostname(), uriPath), bytes.NewBuffer(dataBytes.Bytes()))
+// GetClients gets the client users in the account.
+func GetClients() {
+	clients, err := Clients(context.Background(), ListOptions{})
 	if err != nil {
 		log.Fatalln(err)
 	}
 
-	req.Header.Add("Authorization", getAuthorizationHeader())
-	req.Header.Add("Content-Type", "application/json")
+	for i := range clients {
+		fmt.Printf("%s %s\n", clients[i].ID, clients[i].Email)
+	}
+}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Fatalln(err)
+// CreateClient creates a client user in the account and returns it. When the email address is already in use the
+// error is a *UserExistsError, matching ErrUserExists, holding the existing user's ID.
+func CreateClient(ctx context.Context, c ClientUser, opts ...UserOption) (*User, error) {
+	user := userBody{
+		Email:             c.Email,
+		FirstName:         c.FirstName,
+		LastName:          c.LastName,
+		Company:           c.Company,
+		ClientPassword:    c.Password,
+		CanResetPassword:  c.CanResetPassword,
+		CanViewMySettings: c.CanViewMySettings,
 	}
-	defer resp.Body.Close()
 
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		log.Fatalln(err)
+	o := newUserOptions(opts)
+	uriPath := "/sf/v3/Users"
+	if params := o.params(); len(params) > 0 {
+		uriPath += "?" + params.Encode()
 	}
 
-	items := baseObject{}
-	err = json.Unmarshal(body, &items)
-	if err != nil {
-		log.Fatalln(err)
+	var created User
+	if err := call(ctx, "POST", uriPath, user, &created); err != nil {
+		return nil, userExistsError(ctx, c.Email, err)
 	}
 
-	// Zero error handling
-	fmt.Println(resp.Status)
-	fmt.Printf("Created Client %s\n", items.ID)
+	return &created, o.sendWelcome(ctx, &created)
 }
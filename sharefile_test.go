@@ -0,0 +1,149 @@
+package go-sharefile
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestItemExpiration(t *testing.T) {
+	// The fake keeps the item's expiration, applying each PATCH the way the API does.
+	var stored time.Time
+	var sent []string
+	fakeAPI(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PATCH" {
+			var body map[string]json.RawMessage
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Error(err)
+			}
+			raw, ok := body["ExpirationDate"]
+			if !ok {
+				t.Error("update sent without ExpirationDate")
+			}
+			sent = append(sent, string(raw))
+			if err := json.Unmarshal(raw, &stored); err != nil {
+				t.Error(err)
+			}
+		}
+		writeJSON(t, w, map[string]interface{}{"Id": "fo1", "ExpirationDate": stored})
+	}))
+
+	ctx := context.Background()
+	set := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	changed := set.AddDate(0, 0, 90)
+
+	steps := []struct {
+		name    string
+		to      time.Time
+		expires bool
+	}{
+		{"set", set, true},
+		{"change", changed, true},
+		{"clear", NeverExpires, false},
+	}
+
+	for _, step := range steps {
+		to := step.to
+		item, err := UpdateItem(ctx, "fo1", ItemUpdate{ExpirationDate: &to})
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if !item.ExpirationDate.Equal(step.to) {
+			t.Errorf("%s: expiration read back as %s, want %s", step.name, item.ExpirationDate, step.to)
+		}
+		if item.Expires() != step.expires {
+			t.Errorf("%s: Expires() = %t, want %t", step.name, item.Expires(), step.expires)
+		}
+	}
+
+	want := []string{`"2025-09-01T00:00:00Z"`, `"2025-11-30T00:00:00Z"`, `"9999-12-31T23:59:59.9999999Z"`}
+	for i := range want {
+		if i >= len(sent) || sent[i] != want[i] {
+			t.Errorf("sent expirations %v, want %v", sent, want)
+			break
+		}
+	}
+}
+
+func TestCreateFolderExpiration(t *testing.T) {
+	var body map[string]interface{}
+	fakeAPI(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body = nil
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		writeJSON(t, w, map[string]interface{}{"Id": "fo2", "Name": "Uploads"})
+	}))
+
+	expires := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := CreateFolder(context.Background(), "fo1", "Uploads", "", WithFolderExpiration(expires)); err != nil {
+		t.Fatal(err)
+	}
+	if body["ExpirationDate"] != "2025-09-01T00:00:00Z" {
+		t.Errorf("folder created with ExpirationDate %v", body["ExpirationDate"])
+	}
+
+	if _, err := CreateFolder(context.Background(), "fo1", "Uploads", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := body["ExpirationDate"]; ok {
+		t.Errorf("folder without an expiration sent ExpirationDate %v", body["ExpirationDate"])
+	}
+}
+
+// Content sniffed without help from a file name extension.
+var sniffTests = []struct {
+	name    string
+	content string
+	want    string
+}{
+	{"empty", "", "text/plain; charset=utf-8"},
+	{"short binary", "\x00\x01\x02", "application/octet-stream"},
+	{"pdf", "%PDF-1.7\n%\xe2\xe3\xcf\xd3\n", "application/pdf"},
+	{"zip", "PK\x03\x04\x14\x00\x00\x00\x08\x00", "application/zip"},
+	{"png", "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR", "image/png"},
+	{"csv", "id,name\n1,alpha\n2,beta\n", "text/plain; charset=utf-8"},
+	// Only the first 512 bytes count: text followed by a NUL byte past the window is still text.
+	{"longer than the window", strings.Repeat("a", sniffLen) + "\x00", "text/plain; charset=utf-8"},
+}
+
+func TestGetContentType(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, tt := range sniffTests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := filepath.Join(dir, strings.ReplaceAll(tt.name, " ", "-"))
+			if err := os.WriteFile(f, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			got, err := getContentType(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("getContentType = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("extension first", func(t *testing.T) {
+		f := filepath.Join(dir, "report.pdf")
+		if err := os.WriteFile(f, []byte("not really a pdf"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := getContentType(f); got != "application/pdf" {
+			t.Errorf("getContentType = %q, want application/pdf from the extension", got)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := getContentType(filepath.Join(dir, "missing")); err == nil {
+			t.Error("no error for a missing file")
+		}
+	})
+}
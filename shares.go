@@ -0,0 +1,505 @@
+package go-sharefile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/mail"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrFolderExpired is returned when asked to collect files into a folder that has already expired.
+var ErrFolderExpired = errors.New("sharefile: folder has expired")
+
+// UnlimitedDownloads as a share's MaxDownloads lets it be downloaded any number of times.
+const UnlimitedDownloads = -1
+
+// Kinds of share.
+const (
+	// ShareTypeSend shares items for recipients to download.
+	ShareTypeSend = "Send"
+	// ShareTypeRequest asks recipients to upload files into a folder.
+	ShareTypeRequest = "Request"
+)
+
+// Share is a link giving people outside the account access to items. URI is the link to hand out, not the API
+// resource; AliasID identifies the recipient alias the link was made for, if any. Items are only filled in when
+// expanded, as GetShare does. A MaxDownloads of zero or less means downloads aren't limited, and an ExpirationDate
+// of NeverExpires that the link doesn't expire. TotalDownloads and LastAccessed count every recipient together,
+// anonymous ones included; ShareActivity breaks them down by recipient.
+type Share struct {
+	ID              string    `json:"Id"`
+	ShareType       string    `json:"ShareType"`
+	Title           string    `json:"Title"`
+	URI             string    `json:"Uri"`
+	AliasID         string    `json:"AliasID"`
+	CreationDate    time.Time `json:"CreationDate"`
+	ExpirationDate  time.Time `json:"ExpirationDate"`
+	MaxDownloads    int       `json:"MaxDownloads"`
+	TotalDownloads  int       `json:"TotalDownloads"`
+	LastAccessed    time.Time `json:"LastDateAccessed"`
+	MaxUploads      int       `json:"MaxUploads"`
+	RequireLogin    bool      `json:"RequireLogin"`
+	RequireUserInfo bool      `json:"RequireUserInfo"`
+	IsViewOnly      bool      `json:"IsViewOnly"`
+	NotifyOnAccess  bool      `json:"NotifyOnAccess"`
+	NotifyOnUpload  bool      `json:"NotifyOnUpload"`
+	Creator         *User     `json:"Creator"`
+	Items           []Item    `json:"Items"`
+	// Recipients are the aliases made for each address the share was emailed to.
+	Recipients []ShareAlias `json:"Recipients"`
+}
+
+// ShareAlias identifies one recipient of a share emailed by ShareFile. URI is the link sent to that recipient, so
+// their downloads can be told apart from everyone else's.
+type ShareAlias struct {
+	ID    string `json:"Id"`
+	Email string `json:"Email"`
+	URI   string `json:"Uri"`
+	User  *User  `json:"User"`
+}
+
+// IsExpired reports whether the share's link no longer works because its expiration date has passed.
+func (s *Share) IsExpired() bool {
+	return !s.ExpirationDate.IsZero() && s.ExpirationDate.Before(time.Now())
+}
+
+// DownloadsRemaining returns how many more downloads the share allows, or -1 when they aren't limited.
+func (s *Share) DownloadsRemaining() int {
+	if s.MaxDownloads <= 0 {
+		return -1
+	}
+	if s.TotalDownloads >= s.MaxDownloads {
+		return 0
+	}
+	return s.MaxDownloads - s.TotalDownloads
+}
+
+// Options returns the settings of the share in the form CreateSendShare and CreateRequestShare take, to make a
+// share like it. Passwords aren't reported by the API, so Password is always empty.
+func (s *Share) Options() ShareOptions {
+	return ShareOptions{
+		Title:            s.Title,
+		ExpirationDate:   s.ExpirationDate,
+		MaxDownloads:     s.MaxDownloads,
+		MaxFiles:         s.MaxUploads,
+		RequireLogin:     s.RequireLogin,
+		RequireUserInfo:  s.RequireUserInfo,
+		IsViewOnly:       s.IsViewOnly,
+		NotifyOnDownload: s.NotifyOnAccess,
+		NotifyOnUpload:   s.NotifyOnUpload,
+	}
+}
+
+// Collection response wrapping a list of shares, internal package use.
+type shareFeed struct {
+	Shares []Share `json:"value"`
+}
+
+// ShareOptions are the settings of a new share. A zero ExpirationDate leaves the account's default expiration, while
+// NeverExpires makes a link that doesn't expire. A zero MaxDownloads leaves the account's default and
+// UnlimitedDownloads removes the limit; a zero MaxFiles sets no limit. MaxFiles and NotifyOnUpload only apply to
+// Request shares, MaxDownloads, IsViewOnly and NotifyOnDownload to Send shares. CcSender is only used by
+// SendShareEmail. IsViewOnly, which watermarks and blocks downloads, and Password depend on the account's plan.
+type ShareOptions struct {
+	Title            string
+	ExpirationDate   time.Time
+	MaxDownloads     int
+	MaxFiles         int
+	RequireLogin     bool
+	RequireUserInfo  bool
+	NotifyOnDownload bool
+	NotifyOnUpload   bool
+	CcSender         bool
+	IsViewOnly       bool
+	Password         string
+}
+
+// Struct for use in share POST activities
+type shareBody struct {
+	ShareType       string
+	Title           string     `json:",omitempty"`
+	Items           []itemRef  `json:",omitempty"`
+	Parent          *itemRef   `json:",omitempty"`
+	ExpirationDate  *time.Time `json:",omitempty"`
+	MaxDownloads    int        `json:",omitempty"`
+	MaxUploads      int        `json:",omitempty"`
+	RequireLogin    bool
+	RequireUserInfo bool
+	IsViewOnly      bool   `json:",omitempty"`
+	Password        string `json:",omitempty"`
+	NotifyOnAccess  bool
+	NotifyOnUpload  bool
+}
+
+// Returns the names of the settings in a share creation body that a plan may not allow, internal package use.
+func (b shareBody) fields() []string {
+	var fields []string
+	if b.ExpirationDate != nil {
+		fields = append(fields, "ExpirationDate")
+	}
+	if b.MaxDownloads != 0 {
+		fields = append(fields, "MaxDownloads")
+	}
+	if b.MaxUploads != 0 {
+		fields = append(fields, "MaxUploads")
+	}
+	if b.RequireLogin {
+		fields = append(fields, "RequireLogin")
+	}
+	if b.RequireUserInfo {
+		fields = append(fields, "RequireUserInfo")
+	}
+	if b.IsViewOnly {
+		fields = append(fields, "IsViewOnly")
+	}
+	if b.Password != "" {
+		fields = append(fields, "Password")
+	}
+	return fields
+}
+
+// Names the setting the API refused when it rejects a share's options, internal package use. fields are the names of
+// the settings that were sent; when the API's message doesn't name one of them, they are all listed.
+func shareOptionError(err error, fields []string) error {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || len(fields) == 0 {
+		return err
+	}
+	if apiErr.StatusCode != http.StatusBadRequest && apiErr.StatusCode != http.StatusForbidden {
+		return err
+	}
+
+	msg := strings.ToLower(apiErr.Code + " " + apiErr.Message)
+	for _, f := range fields {
+		if strings.Contains(msg, strings.ToLower(f)) {
+			return fmt.Errorf("sharefile: share option %s rejected: %w", f, err)
+		}
+	}
+
+	return fmt.Errorf("sharefile: share options rejected, one of %s may not be allowed on this account: %w", strings.Join(fields, ", "), err)
+}
+
+// Returns the share creation body for the options, internal package use.
+func (o ShareOptions) body(shareType string) shareBody {
+	body := shareBody{
+		ShareType:       shareType,
+		Title:           o.Title,
+		RequireLogin:    o.RequireLogin,
+		RequireUserInfo: o.RequireUserInfo,
+		Password:        o.Password,
+	}
+	if shareType == ShareTypeRequest {
+		body.MaxUploads = o.MaxFiles
+		body.NotifyOnUpload = o.NotifyOnUpload
+	} else {
+		body.MaxDownloads = o.MaxDownloads
+		body.IsViewOnly = o.IsViewOnly
+		body.NotifyOnAccess = o.NotifyOnDownload
+	}
+	if !o.ExpirationDate.IsZero() {
+		body.ExpirationDate = &o.ExpirationDate
+	}
+	return body
+}
+
+// CreateSendShare creates a link for downloading the given items and returns it. The items may come from different
+// folders.
+func CreateSendShare(ctx context.Context, itemIDs []string, opts ShareOptions) (*Share, error) {
+	if len(itemIDs) == 0 {
+		return nil, fmt.Errorf("sharefile: no items to share")
+	}
+
+	body := opts.body(ShareTypeSend)
+	for _, id := range itemIDs {
+		body.Items = append(body.Items, itemRef{ID: id})
+	}
+
+	var share Share
+	if err := call(ctx, "POST", "/sf/v3/Shares", body, &share); err != nil {
+		return nil, shareOptionError(err, body.fields())
+	}
+
+	return &share, nil
+}
+
+// CreateRequestShare creates a link for uploading files into a folder and returns it; the share's URI is the address
+// to send to whoever is providing the files. The folder is checked first, so a deleted folder fails with ErrNotFound
+// and an expired one with ErrFolderExpired instead of leaving a link that can't accept uploads.
+func CreateRequestShare(ctx context.Context, destinationFolderID string, opts ShareOptions) (*Share, error) {
+	folder, err := GetItemByID(ctx, destinationFolderID, NewQuery().Select("Id", "IsDeleted", "ExpirationDate"))
+	if err != nil {
+		return nil, err
+	}
+	if folder.IsDeleted {
+		return nil, fmt.Errorf("sharefile: folder %s is deleted: %w", destinationFolderID, ErrNotFound)
+	}
+	if folder.Expires() && folder.ExpirationDate.Before(time.Now()) {
+		return nil, fmt.Errorf("sharefile: folder %s expired %s: %w", destinationFolderID, folder.ExpirationDate.Format(time.RFC3339), ErrFolderExpired)
+	}
+
+	body := opts.body(ShareTypeRequest)
+	body.Parent = &itemRef{ID: destinationFolderID}
+
+	var share Share
+	if err := call(ctx, "POST", "/sf/v3/Shares", body, &share); err != nil {
+		return nil, shareOptionError(err, body.fields())
+	}
+
+	return &share, nil
+}
+
+// Shares returns the shares created by the current user, expired ones included, fetching as many pages as needed.
+// Sorting isn't supported.
+func Shares(ctx context.Context, opts ListOptions) ([]Share, error) {
+	return listShares(ctx, "/sf/v3/Shares", opts)
+}
+
+// Returns every share listed at uriPath, internal package use.
+func listShares(ctx context.Context, uriPath string, opts ListOptions) ([]Share, error) {
+	shares := []Share{}
+	err := opts.pageAll("shares", func(q *Query) (int, error) {
+		var feed shareFeed
+		if err := call(ctx, "GET", withQuery(uriPath, q), nil, &feed); err != nil {
+			return 0, err
+		}
+		shares = append(shares, feed.Shares...)
+		return len(feed.Shares), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return shares, nil
+}
+
+// GetShare returns a share with the items it contains.
+func GetShare(ctx context.Context, shareID string) (*Share, error) {
+	uriPath := withQuery(fmt.Sprintf("/sf/v3/Shares(%s)", shareID), NewQuery().Expand("Items"))
+
+	var share Share
+	if err := call(ctx, "GET", uriPath, nil, &share); err != nil {
+		return nil, err
+	}
+
+	return &share, nil
+}
+
+// DeleteShare removes a share, so its link stops working immediately. The shared items themselves are kept.
+func DeleteShare(ctx context.Context, shareID string) error {
+	return call(ctx, "DELETE", fmt.Sprintf("/sf/v3/Shares(%s)", shareID), nil, nil)
+}
+
+// ExpirationDays the send endpoint takes for a share that doesn't expire.
+const sendNeverExpires = -1
+
+// Struct for use in share send POST activities
+type shareSendBody struct {
+	Items            []string
+	Emails           []string
+	Subject          string
+	Body             string
+	Title            string `json:",omitempty"`
+	CcSender         bool
+	NotifyOnDownload bool
+	RequireLogin     bool
+	RequireUserInfo  bool
+	IsViewOnly       bool
+	MaxDownloads     int `json:",omitempty"`
+	ExpirationDays   int `json:",omitempty"`
+}
+
+// Returns the names of the settings in a share send body that a plan may not allow, internal package use.
+func (b shareSendBody) fields() []string {
+	var fields []string
+	if b.ExpirationDays != 0 {
+		fields = append(fields, "ExpirationDays")
+	}
+	if b.MaxDownloads != 0 {
+		fields = append(fields, "MaxDownloads")
+	}
+	if b.RequireLogin {
+		fields = append(fields, "RequireLogin")
+	}
+	if b.RequireUserInfo {
+		fields = append(fields, "RequireUserInfo")
+	}
+	if b.IsViewOnly {
+		fields = append(fields, "IsViewOnly")
+	}
+	return fields
+}
+
+// SendShareEmail shares items with the recipients and has ShareFile email each of them a link, returning the share with
+// an alias per recipient so their activity can be followed. Every address is checked before anything is sent. The
+// expiration date is rounded up to whole days, the unit the send endpoint takes, and MaxDownloads caps the downloads
+// of each recipient. The send endpoint doesn't take a password, so setting Password is an error.
+func SendShareEmail(ctx context.Context, itemIDs []string, recipients []string, subject, body string, opts ShareOptions) (*Share, error) {
+	if len(itemIDs) == 0 {
+		return nil, fmt.Errorf("sharefile: no items to share")
+	}
+	if len(recipients) == 0 {
+		return nil, fmt.Errorf("sharefile: no recipients to send the share to")
+	}
+	if opts.Password != "" {
+		return nil, fmt.Errorf("sharefile: shares sent by email can't have a password")
+	}
+
+	emails := make([]string, 0, len(recipients))
+	for _, r := range recipients {
+		addr, err := mail.ParseAddress(r)
+		if err != nil {
+			return nil, fmt.Errorf("sharefile: recipient %q: %w", r, err)
+		}
+		emails = append(emails, addr.Address)
+	}
+
+	send := shareSendBody{
+		Items:            itemIDs,
+		Emails:           emails,
+		Subject:          subject,
+		Body:             body,
+		Title:            opts.Title,
+		CcSender:         opts.CcSender,
+		NotifyOnDownload: opts.NotifyOnDownload,
+		RequireLogin:     opts.RequireLogin,
+		RequireUserInfo:  opts.RequireUserInfo,
+		IsViewOnly:       opts.IsViewOnly,
+		// UnlimitedDownloads is also what the send endpoint takes for no limit.
+		MaxDownloads: opts.MaxDownloads,
+	}
+	switch {
+	case opts.ExpirationDate.IsZero():
+	case opts.ExpirationDate.Year() >= NeverExpires.Year():
+		send.ExpirationDays = sendNeverExpires
+	default:
+		days := int(math.Ceil(time.Until(opts.ExpirationDate).Hours() / 24))
+		if days < 1 {
+			return nil, fmt.Errorf("sharefile: share expiration %s is in the past", opts.ExpirationDate.Format(time.RFC3339))
+		}
+		send.ExpirationDays = days
+	}
+
+	uriPath := withQuery("/sf/v3/Shares/Send", NewQuery().Expand("Recipients"))
+
+	var share Share
+	if err := call(ctx, "POST", uriPath, send, &share); err != nil {
+		return nil, shareOptionError(err, send.fields())
+	}
+
+	return &share, nil
+}
+
+// ShareUpdate holds the settings UpdateShare changes. Nil fields are left as they are. Set ExpirationDate to
+// NeverExpires to stop the link expiring, and MaxDownloads to UnlimitedDownloads to lift its download cap.
+type ShareUpdate struct {
+	Title           *string    `json:",omitempty"`
+	ExpirationDate  *time.Time `json:",omitempty"`
+	MaxDownloads    *int       `json:",omitempty"`
+	RequireLogin    *bool      `json:",omitempty"`
+	RequireUserInfo *bool      `json:",omitempty"`
+	IsViewOnly      *bool      `json:",omitempty"`
+	NotifyOnAccess  *bool      `json:",omitempty"`
+	NotifyOnUpload  *bool      `json:",omitempty"`
+}
+
+// Returns the names of the settings the update changes that a plan may not allow, internal package use.
+func (u ShareUpdate) fields() []string {
+	var fields []string
+	if u.ExpirationDate != nil {
+		fields = append(fields, "ExpirationDate")
+	}
+	if u.MaxDownloads != nil {
+		fields = append(fields, "MaxDownloads")
+	}
+	if u.RequireLogin != nil {
+		fields = append(fields, "RequireLogin")
+	}
+	if u.RequireUserInfo != nil {
+		fields = append(fields, "RequireUserInfo")
+	}
+	if u.IsViewOnly != nil {
+		fields = append(fields, "IsViewOnly")
+	}
+	return fields
+}
+
+// UpdateShare changes the settings of an existing share, such as extending its expiration or raising its download
+// cap, and returns the updated share. The link stays the same.
+func UpdateShare(ctx context.Context, shareID string, updates ShareUpdate) (*Share, error) {
+	var share Share
+	if err := call(ctx, "PATCH", fmt.Sprintf("/sf/v3/Shares(%s)", shareID), updates, &share); err != nil {
+		return nil, shareOptionError(err, updates.fields())
+	}
+
+	return &share, nil
+}
+
+// ShareAccessRecord is one download of an item of a share. AliasID and Email identify the recipient, and are empty for
+// downloads through the share's public link.
+type ShareAccessRecord struct {
+	AliasID    string
+	Email      string
+	ItemID     string
+	AccessDate time.Time
+	IPAddress  string
+}
+
+// Recipient of a share with the downloads made through its alias, internal package use.
+type shareRecipient struct {
+	ID            string `json:"Id"`
+	Email         string `json:"Email"`
+	User          *User  `json:"User"`
+	AccessRecords []struct {
+		ItemID     string    `json:"ItemId"`
+		AccessDate time.Time `json:"AccessDate"`
+		IPAddress  string    `json:"IPAddress"`
+	} `json:"AccessRecords"`
+}
+
+// Collection response wrapping a list of share recipients, internal package use.
+type shareRecipientFeed struct {
+	Recipients []shareRecipient `json:"value"`
+}
+
+// ShareActivity returns the downloads made from a share, recipient by recipient, oldest first within each recipient.
+// Shares opened anonymously may only keep aggregate counts, in which case fewer records come back than the share's
+// TotalDownloads.
+func ShareActivity(ctx context.Context, shareID string) ([]ShareAccessRecord, error) {
+	uriPath := withQuery(fmt.Sprintf("/sf/v3/Shares(%s)/Recipients", shareID), NewQuery().Expand("User", "AccessRecords"))
+
+	var feed shareRecipientFeed
+	if err := call(ctx, "GET", uriPath, nil, &feed); err != nil {
+		return nil, err
+	}
+
+	records := []ShareAccessRecord{}
+	for _, r := range feed.Recipients {
+		email := r.Email
+		if email == "" && r.User != nil {
+			email = r.User.Email
+		}
+
+		start := len(records)
+		for _, a := range r.AccessRecords {
+			records = append(records, ShareAccessRecord{
+				AliasID:    r.ID,
+				Email:      email,
+				ItemID:     a.ItemID,
+				AccessDate: a.AccessDate,
+				IPAddress:  a.IPAddress,
+			})
+		}
+
+		own := records[start:]
+		sort.SliceStable(own, func(i, j int) bool {
+			return own[i].AccessDate.Before(own[j].AccessDate)
+		})
+	}
+
+	return records, nil
+}
@@ -0,0 +1,62 @@
+package go-sharefile
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSendShareEmail(t *testing.T) {
+	var got shareSendBody
+	fakeAPI(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/sf/v3/Shares/Send" {
+			http.NotFound(w, r)
+			return
+		}
+		got = shareSendBody{}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+		if got.IsViewOnly {
+			w.WriteHeader(http.StatusForbidden)
+			writeJSON(t, w, map[string]interface{}{"code": "Forbidden", "message": map[string]string{"value": "Not allowed"}})
+			return
+		}
+		writeJSON(t, w, map[string]string{"Id": "s1"})
+	}))
+
+	ctx := context.Background()
+	items, to := []string{"fi1"}, []string{"Ann <ann@example.com>"}
+
+	opts := ShareOptions{Title: "Q3 report", ExpirationDate: NeverExpires, MaxDownloads: UnlimitedDownloads}
+	if _, err := SendShareEmail(ctx, items, to, "Report", "Here it is", opts); err != nil {
+		t.Fatal(err)
+	}
+	if got.Title != "Q3 report" || got.ExpirationDays != -1 || got.MaxDownloads != -1 || got.Emails[0] != "ann@example.com" {
+		t.Errorf("sent %+v", got)
+	}
+
+	opts = ShareOptions{ExpirationDate: time.Now().Add(36 * time.Hour)}
+	if _, err := SendShareEmail(ctx, items, to, "Report", "Here it is", opts); err != nil {
+		t.Fatal(err)
+	}
+	if got.ExpirationDays != 2 || got.MaxDownloads != 0 {
+		t.Errorf("sent %+v", got)
+	}
+
+	// Only the settings the send endpoint was given are named when it refuses one.
+	opts = ShareOptions{IsViewOnly: true, ExpirationDate: NeverExpires}
+	_, err := SendShareEmail(ctx, items, to, "Report", "Here it is", opts)
+	if err == nil || !strings.Contains(err.Error(), "one of ExpirationDays, IsViewOnly may not") {
+		t.Errorf("refused options give %v", err)
+	}
+
+	got = shareSendBody{}
+	opts = ShareOptions{Password: "secret"}
+	if _, err := SendShareEmail(ctx, items, to, "Report", "Here it is", opts); err == nil || got.Emails != nil {
+		t.Errorf("password gives %v and sends %+v", err, got)
+	}
+}
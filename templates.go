@@ -0,0 +1,57 @@
+package go-sharefile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// FolderTemplate is a predefined folder structure that can be stamped out under new folders.
+type FolderTemplate struct {
+	ID          string `json:"Id"`
+	Name        string `json:"Name"`
+	Description string `json:"Description"`
+}
+
+// Collection response wrapping a list of folder templates, internal package use.
+type folderTemplateFeed struct {
+	Templates []FolderTemplate `json:"value"`
+}
+
+// Struct for use in folder template apply activities
+type templateApplyBody struct {
+	FolderIds []string
+}
+
+// FolderTemplates returns the folder templates available to the account. Accounts without folder templates enabled
+// get an empty list.
+func FolderTemplates(ctx context.Context) ([]FolderTemplate, error) {
+	var feed folderTemplateFeed
+	err := call(ctx, "GET", "/sf/v3/FolderTemplates", nil, &feed)
+	if errors.Is(err, ErrForbidden) || errors.Is(err, ErrNotFound) {
+		return []FolderTemplate{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return feed.Templates, nil
+}
+
+// CreateFolderFromTemplate creates a new folder in the given parent folder and applies a folder template to it. The
+// returned folder includes the children created from the template.
+func CreateFolderFromTemplate(ctx context.Context, parentID, name, templateID string) (*Item, error) {
+	created, err := CreateFolder(ctx, parentID, name, "")
+	if err != nil {
+		return nil, err
+	}
+
+	apply := templateApplyBody{
+		FolderIds: []string{created.ID},
+	}
+	if err := call(ctx, "POST", fmt.Sprintf("/sf/v3/FolderTemplates(%s)/BulkApply", templateID), apply, nil); err != nil {
+		return created, fmt.Errorf("sharefile: folder %s created but template %s not applied: %w", created.ID, templateID, err)
+	}
+
+	return GetItemByID(ctx, created.ID, NewQuery().Expand("Children"))
+}
@@ -0,0 +1,564 @@
+package go-sharefile
+
+import (
+	"bufio"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrChecksumMismatch is returned when the MD5 hash ShareFile stored for a file differs from the one computed locally.
+var ErrChecksumMismatch = errors.New("sharefile: checksum mismatch")
+
+// UploadOption configures an upload.
+type UploadOption func(*uploadOptions)
+
+// Options collected from UploadOption values, internal package use.
+type uploadOptions struct {
+	unzip          bool
+	expirationDays int
+	conflict       ConflictPolicy
+	chunkSize      int64
+	parallel       int
+	threaded       bool
+	raw            bool
+	remoteName     string
+	concurrency    int
+	followSymlinks bool
+	skip           func(path string, d fs.DirEntry) bool
+	checksum       bool
+	clientCreated  time.Time
+	clientModified time.Time
+	notify         bool
+	details        string
+	batchID        string
+	batchLast      bool
+	skipUnchanged  bool
+	skipHashOnly   bool
+	localHash      string
+	limiters       []*RateLimiter
+}
+
+// WithUnzip asks ShareFile to extract an uploaded zip archive into the destination folder instead of storing the
+// archive itself. Extraction of large archives continues on the server after the upload returns.
+func WithUnzip() UploadOption {
+	return func(o *uploadOptions) {
+		o.unzip = true
+	}
+}
+
+// WithExpirationDays makes the uploaded file expire the given number of days after the upload.
+func WithExpirationDays(days int) UploadOption {
+	return func(o *uploadOptions) {
+		o.expirationDays = days
+	}
+}
+
+// WithUploadConflict sets what happens when the folder already has a file with the same name. The default,
+// ConflictRename, leaves the naming to ShareFile, which stores the upload as a numbered copy or a new version
+// depending on the account's versioning settings. The upload endpoint has no way to refuse a name, so ConflictFail
+// looks for it before uploading: it costs a request, and a file created by someone else in between is still named by
+// ShareFile.
+func WithUploadConflict(p ConflictPolicy) UploadOption {
+	return func(o *uploadOptions) {
+		o.conflict = p
+	}
+}
+
+// WithRawUpload sends the file as the plain body of the upload POST instead of multipart/form-data. Some storage zones
+// only accept this form from scripts, and it avoids the multipart framing on large binaries.
+func WithRawUpload() UploadOption {
+	return func(o *uploadOptions) {
+		o.raw = true
+	}
+}
+
+// WithRemoteName stores the file under the given name instead of the local file's base name. It applies to UploadFile
+// and the calls built on it; Upload takes the name as an argument.
+func WithRemoteName(name string) UploadOption {
+	return func(o *uploadOptions) {
+		o.remoteName = name
+	}
+}
+
+// WithChecksum computes the MD5 hash of the file while it is sent and, once the upload completes, compares it with the
+// hash ShareFile stored, failing with an error wrapping ErrChecksumMismatch when they differ. Threaded uploads also
+// have each chunk checked by the server as it arrives.
+func WithChecksum() UploadOption {
+	return func(o *uploadOptions) {
+		o.checksum = true
+	}
+}
+
+// WithClientTimes records the file's creation and modification times on the upload, so the stored file keeps them
+// rather than the time of the upload. Zero times are left out. UploadFile and UploadDirectory pass the local file's
+// modification time by default.
+func WithClientTimes(created, modified time.Time) UploadOption {
+	return func(o *uploadOptions) {
+		o.clientCreated = created
+		o.clientModified = modified
+	}
+}
+
+// WithNotify sends ShareFile's standard upload notification email to the folder's subscribers when the upload
+// completes. Storage zones that don't send notifications ignore it.
+func WithNotify() UploadOption {
+	return func(o *uploadOptions) {
+		o.notify = true
+	}
+}
+
+// WithDetails attaches a note to the upload, shown with the file and included in the upload notification.
+func WithDetails(msg string) UploadOption {
+	return func(o *uploadOptions) {
+		o.details = msg
+	}
+}
+
+// WithSkipIfUnchanged makes UploadFile leave a file alone when the folder already holds a file of the same name, size
+// and MD5 hash, returning the existing file with Skipped set instead. The local hash is only computed when the sizes
+// match, and is reused by WithChecksum when the file is uploaded after all. Remote files without a stored hash are
+// compared by size and modification time instead, unless WithSkipByHashOnly is given. A remote file that differs is
+// overwritten with the local one, as a sync would, unless WithUploadConflict sets another policy.
+func WithSkipIfUnchanged() UploadOption {
+	return func(o *uploadOptions) {
+		o.skipUnchanged = true
+	}
+}
+
+// WithSkipByHashOnly makes WithSkipIfUnchanged upload files whose remote copy has no stored hash rather than
+// comparing their modification times.
+func WithSkipByHashOnly() UploadOption {
+	return func(o *uploadOptions) {
+		o.skipHashOnly = true
+	}
+}
+
+// Passes on a local MD5 hash already computed for the file, so WithChecksum needn't hash it again, internal package
+// use.
+func withLocalHash(sum string) UploadOption {
+	return func(o *uploadOptions) {
+		o.localHash = sum
+	}
+}
+
+// Layout of client times on the upload specification request, internal package use.
+const clientTimeLayout = "2006-01-02T15:04:05Z"
+
+// Applies upload options over the defaults, internal package use.
+func newUploadOptions(opts []UploadOption) *uploadOptions {
+	o := &uploadOptions{conflict: ConflictRename}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Returns the query parameters for the upload specification request, internal package use.
+func (o *uploadOptions) specParams() url.Values {
+	params := url.Values{}
+	if o.unzip {
+		params.Set("unzip", "true")
+	}
+	if o.conflict == ConflictOverwrite {
+		params.Set("overwrite", "true")
+	}
+	if o.expirationDays > 0 {
+		params.Set("expirationDays", strconv.Itoa(o.expirationDays))
+	}
+	if !o.clientCreated.IsZero() {
+		params.Set("clientCreatedDateUTC", o.clientCreated.UTC().Format(clientTimeLayout))
+	}
+	if !o.clientModified.IsZero() {
+		params.Set("clientModifiedDateUTC", o.clientModified.UTC().Format(clientTimeLayout))
+	}
+	if o.notify {
+		params.Set("notify", "true")
+	}
+	if o.details != "" {
+		params.Set("details", o.details)
+	}
+	if o.batchID != "" {
+		params.Set("isbatch", "true")
+		params.Set("batchId", o.batchID)
+		params.Set("batchLast", strconv.FormatBool(o.batchLast))
+	}
+	if o.raw && !o.threaded {
+		params.Set("raw", "true")
+	}
+	if o.threaded {
+		params.Set("method", "threaded")
+		params.Set("threadCount", strconv.Itoa(o.parallelChunks()))
+	}
+	return params
+}
+
+// Unzip extracts a zip archive already stored in ShareFile into its parent folder. Extraction runs asynchronously on
+// the server; the returned operation describes its progress, and its Message carries any name collisions the API
+// reports. Wait for it with WaitForOperation.
+func Unzip(ctx context.Context, itemID string) (*AsyncOperation, error) {
+	var op AsyncOperation
+	if err := call(ctx, "POST", fmt.Sprintf("/sf/v3/Items(%s)/Unzip", itemID), nil, &op); err != nil {
+		return nil, err
+	}
+
+	return &op, nil
+}
+
+// Number of times an expired upload specification is replaced during one upload, internal package use.
+const maxSpecRenewals = 2
+
+// Reports whether an error from a chunk or upload POST means the upload specification has expired, internal package
+// use. Storage zones refuse expired upload URLs with a client error rather than a distinct code.
+func uploadSpecExpired(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusGone:
+		return true
+	}
+	return false
+}
+
+// Upload specification returned by Items(id)/Upload, internal package use.
+type uploadSpec struct {
+	Method    string `json:"Method"`
+	ChunkURI  string `json:"ChunkUri"`
+	FinishURI string `json:"FinishUri"`
+}
+
+// Upload uploads the contents of r to a folder as a file called name and returns the new file. Size is the number of
+// bytes r will produce, or -1 when it isn't known in advance, in which case the body is sent with chunked transfer
+// encoding instead of a Content-Length. The content type is sniffed from the start of r without consuming it.
+//
+// Files of 64 MB and over, and uploads given WithChunkSize or WithParallelChunks, are sent in chunks with the threaded
+// upload method, retrying failed chunks individually. Files larger than the account's MaxUploadSize capability are
+// refused before anything is sent, with an error wrapping ErrUploadTooLarge.
+func Upload(ctx context.Context, folderID string, name string, r io.Reader, size int64, opts ...UploadOption) (*Item, error) {
+	o := newUploadOptions(opts)
+
+	if err := checkUploadSize(ctx, name, size); err != nil {
+		return nil, err
+	}
+
+	if o.conflict == ConflictFail {
+		exists, _, err := ItemExists(ctx, folderID, name)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, fmt.Errorf("sharefile: %q already exists in folder %s: %w", name, folderID, ErrConflict)
+		}
+	}
+
+	body, sum, err := transferUpload(ctx, name, r, size, o, func() (*uploadSpec, error) {
+		return requestUploadSpec(ctx, folderID, name, size, o)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := uploadedItem(ctx, body, folderID, name)
+	if err != nil || !o.checksum {
+		return item, err
+	}
+
+	local := o.localHash
+	if sum != nil {
+		local = hex.EncodeToString(sum.Sum(nil))
+	}
+	stored, err := GetItemByID(ctx, item.ID, NewQuery().Select("Id", "Hash"))
+	if err != nil {
+		return item, err
+	}
+	if !strings.EqualFold(stored.Hash, local) {
+		return item, fmt.Errorf("sharefile: %q uploaded with MD5 %s, stored as %s: %w", name, local, stored.Hash, ErrChecksumMismatch)
+	}
+	item.Hash = local
+
+	return item, nil
+}
+
+// Sends the contents of r to the upload specifications returned by requestSpec, asking for a fresh one when a
+// specification expires, and returns the response of the completed upload along with the MD5 hash of the content when
+// WithChecksum asks for one, internal package use.
+func transferUpload(ctx context.Context, name string, r io.Reader, size int64, o *uploadOptions, requestSpec func() (*uploadSpec, error)) ([]byte, hash.Hash, error) {
+	// Where a seekable reader starts, to go back to if the upload has to be restarted.
+	var start int64
+	if seeker, ok := r.(io.Seeker); ok {
+		start, _ = seeker.Seek(0, io.SeekCurrent)
+	}
+
+	br := bufio.NewReaderSize(r, sniffLen)
+	contentType, err := peekContentType(name, br)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	o.threaded = o.useThreaded(size)
+
+	spec, err := requestSpec()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var content io.Reader = o.rateLimitedReader(ctx, br)
+	var sum hash.Hash
+	if o.checksum && o.localHash == "" {
+		sum = md5.New()
+		content = io.TeeReader(content, sum)
+	}
+
+	var body []byte
+	for renewals := 0; ; renewals++ {
+		switch {
+		case o.threaded:
+			body, err = threadedUpload(ctx, spec, requestSpec, content, size, o)
+		case o.raw:
+			body, err = rawPostUpload(ctx, spec.ChunkURI, contentType, content, size)
+		default:
+			body, err = multipartFormPostUpload(ctx, spec.ChunkURI, name, contentType, content, size)
+		}
+		if err == nil || o.threaded || renewals == maxSpecRenewals || !uploadSpecExpired(err) {
+			break
+		}
+
+		// A single-shot upload can only start over, which needs the content again from its start.
+		seeker, ok := r.(io.Seeker)
+		if !ok {
+			break
+		}
+		if _, serr := seeker.Seek(start, io.SeekStart); serr != nil {
+			break
+		}
+		br.Reset(r)
+		if sum != nil {
+			sum.Reset()
+		}
+		Logger.Printf("upload URL for %q expired, restarting the upload", name)
+
+		if spec, err = requestSpec(); err != nil {
+			break
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return body, sum, nil
+}
+
+// UploadToPath uploads the contents of r to a "/" separated path such as "/Shared/Reports/2025/june.csv", creating any
+// missing folders along the way with EnsureFolderPath. The path is resolved from the top-level container, RootTop. The
+// last segment is the file name, to which the conflict policy of WithUploadConflict applies; size is as for Upload.
+//
+// A path ending in "/" names no file and is rejected, as is a path whose folder part runs into an existing file.
+func UploadToPath(ctx context.Context, remotePath string, r io.Reader, size int64, opts ...UploadOption) (*Item, error) {
+	if strings.HasSuffix(remotePath, "/") {
+		return nil, fmt.Errorf("sharefile: upload path %q has no file name", remotePath)
+	}
+
+	dir, name := path.Split(remotePath)
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+
+	folder, err := EnsureFolderPath(ctx, string(RootTop), dir)
+	if err != nil {
+		return nil, fmt.Errorf("sharefile: upload path %q: %w", remotePath, err)
+	}
+
+	return Upload(ctx, folder.ID, name, r, size, opts...)
+}
+
+// Returns the file in a folder that a local file would be uploaded over, if any, and whether it holds the same content.
+// When the local file's MD5 hash had to be computed to find out it is returned too, internal package use. The local
+// file is left positioned at its start.
+func unchangedRemote(ctx context.Context, file *os.File, info os.FileInfo, folderID, name string, o *uploadOptions) (*Item, bool, string, error) {
+	existing, err := ChildByName(ctx, folderID, name)
+	if errors.Is(err, ErrNotFound) {
+		return nil, false, "", nil
+	}
+	if err != nil {
+		return nil, false, "", err
+	}
+	if !existing.IsFile() {
+		return nil, false, "", nil
+	}
+	if existing.FileSizeBytes != info.Size() {
+		return existing, false, "", nil
+	}
+
+	if existing.Hash == "" {
+		same := !o.skipHashOnly && existing.ClientModifiedDate.Equal(info.ModTime().UTC().Truncate(time.Second))
+		return existing, same, "", nil
+	}
+
+	sum := md5.New()
+	if _, err := io.Copy(sum, file); err != nil {
+		return nil, false, "", err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, false, "", err
+	}
+
+	local := hex.EncodeToString(sum.Sum(nil))
+	if strings.EqualFold(existing.Hash, local) {
+		return existing, true, "", nil
+	}
+
+	return existing, false, local, nil
+}
+
+// Does a raw upload to a url, with the reader streamed as the request body, internal package use. The url is marked
+// for raw mode whether or not it already was. Returns the response body.
+func rawPostUpload(ctx context.Context, u string, contentType string, r io.Reader, size int64) ([]byte, error) {
+	if size >= 0 {
+		r = io.LimitReader(r, size)
+	}
+
+	uri, err := url.Parse(u)
+	if err != nil {
+		return nil, err
+	}
+	params := uri.Query()
+	params.Set("raw", "true")
+	uri.RawQuery = params.Encode()
+
+	req, err := http.NewRequest("POST", uri.String(), r)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.ContentLength = size
+
+	req.Header.Add("Content-Type", contentType)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+// JSON response of a completed upload, internal package use.
+type uploadResponse struct {
+	Error        bool   `json:"error"`
+	ErrorMessage string `json:"errorMessage"`
+	Files        []struct {
+		ID          string `json:"id"`
+		ParentID    string `json:"parentid"`
+		FileName    string `json:"filename"`
+		DisplayName string `json:"displayname"`
+		Size        int64  `json:"size"`
+		MD5         string `json:"md5"`
+	} `json:"value"`
+}
+
+// Returns the uploaded file described by an upload response, looking it up by name when the response doesn't
+// identify it, internal package use.
+func uploadedItem(ctx context.Context, body []byte, folderID, name string) (*Item, error) {
+	var resp uploadResponse
+	if json.Unmarshal(body, &resp) == nil {
+		if resp.Error {
+			return nil, fmt.Errorf("sharefile: upload of %q failed: %s", name, resp.ErrorMessage)
+		}
+		if len(resp.Files) > 1 {
+			return nil, fmt.Errorf("sharefile: upload of %q registered %d files", name, len(resp.Files))
+		}
+		if len(resp.Files) > 0 && resp.Files[0].ID != "" {
+			f := resp.Files[0]
+			item := &Item{
+				ID:            f.ID,
+				Type:          TypeFile,
+				Name:          f.DisplayName,
+				FileName:      f.FileName,
+				FileSizeBytes: f.Size,
+				Hash:          f.MD5,
+				Parent:        &Item{ID: f.ParentID},
+			}
+			if item.Name == "" {
+				item.Name = f.FileName
+			}
+			return item, nil
+		}
+	}
+
+	return ChildByName(ctx, folderID, name)
+}
+
+// Number of bytes http.DetectContentType looks at, internal package use.
+const sniffLen = 512
+
+// Returns the content type of a file being read from br, from the extension of its name when it has a known one,
+// otherwise sniffed from the start of br without consuming it, internal package use.
+func peekContentType(name string, br *bufio.Reader) (string, error) {
+	if ct := mime.TypeByExtension(path.Ext(name)); ct != "" {
+		return ct, nil
+	}
+
+	head, err := br.Peek(sniffLen)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return "", err
+	}
+
+	return http.DetectContentType(head), nil
+}
+
+// Requests the upload specification for a file, internal package use.
+func requestUploadSpec(ctx context.Context, folderID, name string, size int64, o *uploadOptions) (*uploadSpec, error) {
+	// Folders in storage zones take their uploads on the zone's host.
+	specURL, err := itemResourceURL(ctx, folderID, "/Upload")
+	if err != nil {
+		return nil, err
+	}
+
+	params := o.specParams()
+	params.Set("fileName", name)
+	params.Set("responseFormat", "json")
+	if size >= 0 {
+		params.Set("fileSize", strconv.FormatInt(size, 10))
+	}
+	specURL = fmt.Sprintf("%s?%s", specURL, params.Encode())
+
+	req, err := http.NewRequest("GET", specURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	req.Header.Add("Authorization", getAuthorizationHeader())
+
+	var spec uploadSpec
+	if err := do(req, &spec); err != nil {
+		return nil, err
+	}
+	if spec.ChunkURI == "" {
+		return nil, fmt.Errorf("sharefile: no upload URL received for folder %s", folderID)
+	}
+
+	return &spec, nil
+}
@@ -0,0 +1,767 @@
+package go-sharefile
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Fake of the account's API host and storage for upload tests. It serves one folder, fo1, and implements the upload
+// specification, standard, raw and threaded uploads, storing files as ShareFile does: a name already taken becomes a
+// numbered copy unless the upload overwrites.
+type fakeStorage struct {
+	t *testing.T
+
+	// expireAfter makes each upload specification refuse chunks once it has taken that many, as an expired one does.
+	expireAfter int
+	// discard counts uploaded bytes without keeping them, for uploads too large to hold.
+	discard bool
+
+	mu       sync.Mutex
+	files    map[string][]byte
+	sizes    map[string]int64
+	params   map[string]url.Values
+	specs    []*url.URL
+	uploads  map[string]*fakeUpload
+	requests []*http.Request
+}
+
+// Upload in progress against one specification.
+type fakeUpload struct {
+	name   string
+	params url.Values
+	chunks int
+}
+
+func newFakeStorage(t *testing.T) *fakeStorage {
+	s := &fakeStorage{
+		t:       t,
+		files:   map[string][]byte{},
+		sizes:   map[string]int64{},
+		params:  map[string]url.Values{},
+		uploads: map[string]*fakeUpload{},
+	}
+	fakeAPI(t, s)
+	return s
+}
+
+// Chunks of threaded uploads by file name and index, kept across specifications.
+var fakeChunks = struct {
+	sync.Mutex
+	m map[string]map[int][]byte
+}{m: map[string]map[int][]byte{}}
+
+func (s *fakeStorage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, r)
+	s.mu.Unlock()
+
+	switch {
+	case r.URL.Path == "/sf/v3/Items(fo1)":
+		writeJSON(s.t, w, map[string]string{"Id": "fo1"})
+	case r.URL.Path == "/sf/v3/Items(fo1)/Upload":
+		s.spec(w, r)
+	case r.URL.Path == "/sf/v3/Items(fo1)/Children":
+		s.children(w)
+	case strings.HasPrefix(r.URL.Path, "/sf/v3/Items(fi-"):
+		s.mu.Lock()
+		name := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/sf/v3/Items(fi-"), ")")
+		_, ok := s.sizes[name]
+		item := s.item(name)
+		s.mu.Unlock()
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(s.t, w, item)
+	case r.URL.Path == "/upload/chunk":
+		s.chunk(w, r)
+	case r.URL.Path == "/upload/finish":
+		s.finish(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// Answers an upload specification request.
+func (s *fakeStorage) spec(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	s.mu.Lock()
+	u := *r.URL
+	u.Host = r.Host
+	s.specs = append(s.specs, &u)
+	id := strconv.Itoa(len(s.specs))
+	s.uploads[id] = &fakeUpload{name: q.Get("fileName"), params: q}
+	s.mu.Unlock()
+
+	writeJSON(s.t, w, map[string]string{
+		"Method":    q.Get("method"),
+		"ChunkUri":  "https://acme.sf-api.com/upload/chunk?id=" + id,
+		"FinishUri": "https://acme.sf-api.com/upload/finish?id=" + id,
+	})
+}
+
+// Returns the upload a chunk or finish request belongs to, refusing it once expired.
+func (s *fakeStorage) upload(w http.ResponseWriter, r *http.Request, chunk bool) *fakeUpload {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := s.uploads[r.URL.Query().Get("id")]
+	if u == nil {
+		http.NotFound(w, r)
+		return nil
+	}
+	if s.expireAfter > 0 && u.chunks >= s.expireAfter {
+		http.Error(w, "upload expired", http.StatusGone)
+		return nil
+	}
+	if chunk {
+		u.chunks++
+	}
+	return u
+}
+
+// Takes a standard, raw or threaded chunk.
+func (s *fakeStorage) chunk(w http.ResponseWriter, r *http.Request) {
+	u := s.upload(w, r, true)
+	if u == nil {
+		return
+	}
+
+	if u.params.Get("method") == "threaded" {
+		index, _ := strconv.Atoi(r.URL.Query().Get("index"))
+		data, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			// The client gave up on the upload.
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		fakeChunks.Lock()
+		if fakeChunks.m[u.name] == nil {
+			fakeChunks.m[u.name] = map[int][]byte{}
+		}
+		fakeChunks.m[u.name][index] = data
+		fakeChunks.Unlock()
+		return
+	}
+
+	var body io.Reader = r.Body
+	if r.URL.Query().Get("raw") != "true" {
+		_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil {
+			s.t.Error(err)
+			return
+		}
+		part, err := multipart.NewReader(r.Body, params["boundary"]).NextPart()
+		if err != nil {
+			// The client gave up on the upload.
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		body = part
+	}
+
+	s.store(w, u, body)
+}
+
+// Completes a threaded upload.
+func (s *fakeStorage) finish(w http.ResponseWriter, r *http.Request) {
+	u := s.upload(w, r, false)
+	if u == nil {
+		return
+	}
+
+	fakeChunks.Lock()
+	chunks := fakeChunks.m[u.name]
+	delete(fakeChunks.m, u.name)
+	fakeChunks.Unlock()
+
+	var indexes []int
+	for i := range chunks {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	var data bytes.Buffer
+	for n, i := range indexes {
+		if n != i {
+			http.Error(w, fmt.Sprintf("chunk %d missing", n), http.StatusBadRequest)
+			return
+		}
+		data.Write(chunks[i])
+	}
+
+	sum := md5.Sum(data.Bytes())
+	if got := r.URL.Query().Get("fileHash"); got != hex.EncodeToString(sum[:]) {
+		http.Error(w, "file hash "+got+" doesn't match", http.StatusBadRequest)
+		return
+	}
+
+	s.store(w, u, &data)
+}
+
+// Stores an uploaded file and writes the upload response.
+func (s *fakeStorage) store(w http.ResponseWriter, u *fakeUpload, body io.Reader) {
+	var data []byte
+	var n int64
+	var err error
+	if s.discard {
+		n, err = io.Copy(ioutil.Discard, body)
+	} else {
+		data, err = ioutil.ReadAll(body)
+		n = int64(len(data))
+	}
+	if err != nil {
+		// The client gave up on the upload.
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	name := u.name
+	if u.params.Get("overwrite") != "true" {
+		ext := path.Ext(name)
+		for i := 1; ; i++ {
+			if _, taken := s.sizes[name]; !taken {
+				break
+			}
+			name = fmt.Sprintf("%s (%d)%s", strings.TrimSuffix(u.name, ext), i, ext)
+		}
+	}
+	s.files[name] = data
+	s.sizes[name] = n
+	s.params[name] = u.params
+	s.mu.Unlock()
+
+	writeJSON(s.t, w, map[string]interface{}{
+		"error": false,
+		"value": []map[string]interface{}{{
+			"id":          "fi-" + name,
+			"parentid":    "fo1",
+			"filename":    name,
+			"displayname": name,
+			"size":        n,
+		}},
+	})
+}
+
+// Lists the stored files as the folder's children.
+func (s *fakeStorage) children(w http.ResponseWriter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	items := []map[string]interface{}{}
+	for name := range s.sizes {
+		items = append(items, s.item(name))
+	}
+	writeJSON(s.t, w, map[string]interface{}{"value": items})
+}
+
+// Returns the item resource of a stored file, with the client times its upload gave; the caller holds s.mu.
+func (s *fakeStorage) item(name string) map[string]interface{} {
+	item := map[string]interface{}{
+		"Id":            "fi-" + name,
+		"odata.type":    TypeFile,
+		"Name":          name,
+		"FileSizeBytes": s.sizes[name],
+	}
+	if data := s.files[name]; data != nil || s.sizes[name] == 0 {
+		sum := md5.Sum(data)
+		item["Hash"] = hex.EncodeToString(sum[:])
+	}
+	if t := s.params[name].Get("clientCreatedDateUTC"); t != "" {
+		item["ClientCreatedDate"] = t
+	}
+	if t := s.params[name].Get("clientModifiedDateUTC"); t != "" {
+		item["ClientModifiedDate"] = t
+	}
+	return item
+}
+
+// Returns the stored content of a file.
+func (s *fakeStorage) file(name string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, ok := s.files[name]
+	return data, ok
+}
+
+func TestUploadConflictPolicies(t *testing.T) {
+	ctx := context.Background()
+	upload := func(content string, opts ...UploadOption) (*Item, error) {
+		return Upload(ctx, "fo1", "report.pdf", strings.NewReader(content), int64(len(content)), opts...)
+	}
+
+	t.Run("default leaves naming to ShareFile", func(t *testing.T) {
+		fs := newFakeStorage(t)
+		if _, err := upload("first"); err != nil {
+			t.Fatal(err)
+		}
+		item, err := upload("second")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if item.Name == "report.pdf" {
+			t.Errorf("second upload stored as %q", item.Name)
+		}
+		if data, _ := fs.file("report.pdf"); string(data) != "first" {
+			t.Errorf("original holds %q", data)
+		}
+		for _, r := range fs.requests {
+			if r.URL.Path == "/sf/v3/Items(fo1)/Children" {
+				t.Errorf("default upload looked the name up first: %s", r.URL)
+			}
+			if r.URL.Query().Get("overwrite") != "" {
+				t.Errorf("default upload sent overwrite: %s", r.URL)
+			}
+		}
+	})
+
+	t.Run("rename", func(t *testing.T) {
+		fs := newFakeStorage(t)
+		if _, err := upload("first", WithUploadConflict(ConflictRename)); err != nil {
+			t.Fatal(err)
+		}
+		item, err := upload("second", WithUploadConflict(ConflictRename))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if item.Name == "report.pdf" {
+			t.Fatalf("renamed upload stored as the requested name %q", item.Name)
+		}
+		if data, ok := fs.file(item.Name); !ok || string(data) != "second" {
+			t.Errorf("%q holds %q", item.Name, data)
+		}
+		if data, _ := fs.file("report.pdf"); string(data) != "first" {
+			t.Errorf("original holds %q", data)
+		}
+	})
+
+	t.Run("overwrite", func(t *testing.T) {
+		fs := newFakeStorage(t)
+		if _, err := upload("first"); err != nil {
+			t.Fatal(err)
+		}
+		item, err := upload("second", WithUploadConflict(ConflictOverwrite))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if item.Name != "report.pdf" {
+			t.Errorf("overwriting upload stored as %q", item.Name)
+		}
+		if data, _ := fs.file("report.pdf"); string(data) != "second" {
+			t.Errorf("file holds %q after overwrite", data)
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		newFakeStorage(t)
+		if _, err := upload("first", WithUploadConflict(ConflictFail)); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := upload("second", WithUploadConflict(ConflictFail)); !errors.Is(err, ErrConflict) {
+			t.Errorf("upload over an existing name returned %v, want ErrConflict", err)
+		}
+	})
+}
+
+func TestCreateFolderRename(t *testing.T) {
+	existing := map[string]bool{"Uploads": true, "Uploads (2)": true}
+	fakeAPI(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body folderBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		if existing[body.Name] {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		writeJSON(t, w, map[string]string{"Id": "fo2", "Name": body.Name})
+	}))
+
+	folder, err := CreateFolder(context.Background(), "fo1", "Uploads", "", WithFolderConflict(ConflictRename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if folder.Name != "Uploads (3)" {
+		t.Errorf("folder stored as %q, want Uploads (3)", folder.Name)
+	}
+}
+
+// Uploads sparse files of growing size; bytes allocated per upload should stay the same for all of them.
+func BenchmarkMultipartFormPostUpload(b *testing.B) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(ioutil.Discard, r.Body)
+		w.Write([]byte(`{"error":false,"value":[]}`))
+	}))
+	defer srv.Close()
+
+	for _, size := range []int64{1 << 20, 64 << 20, 1 << 30} {
+		b.Run(fmt.Sprintf("%dMB", size>>20), func(b *testing.B) {
+			file, err := ioutil.TempFile(b.TempDir(), "sparse")
+			if err != nil {
+				b.Fatal(err)
+			}
+			defer file.Close()
+			if err := file.Truncate(size); err != nil {
+				b.Fatal(err)
+			}
+
+			b.SetBytes(size)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := file.Seek(0, io.SeekStart); err != nil {
+					b.Fatal(err)
+				}
+				if _, err := multipartFormPostUpload(context.Background(), srv.URL, "sparse.bin", "application/octet-stream", file, size); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestMultipartFormPostUploadContentLength(t *testing.T) {
+	content := strings.Repeat("0123456789", 1000)
+
+	var header string
+	var length int64
+	var encoding []string
+	var received int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("Content-Length")
+		length, encoding = r.ContentLength, r.TransferEncoding
+		received, _ = io.Copy(ioutil.Discard, r.Body)
+	}))
+	defer srv.Close()
+
+	t.Run("known size", func(t *testing.T) {
+		_, err := multipartFormPostUpload(context.Background(), srv.URL, "a.txt", "text/plain", strings.NewReader(content), int64(len(content)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if header != strconv.FormatInt(received, 10) || length != received {
+			t.Errorf("Content-Length %q (%d) for a %d byte body", header, length, received)
+		}
+		if len(encoding) != 0 {
+			t.Errorf("Transfer-Encoding %v with a known size", encoding)
+		}
+		if received <= int64(len(content)) {
+			t.Errorf("body of %d bytes doesn't hold the %d byte file and its framing", received, len(content))
+		}
+	})
+
+	t.Run("unknown size", func(t *testing.T) {
+		_, err := multipartFormPostUpload(context.Background(), srv.URL, "a.txt", "text/plain", strings.NewReader(content), -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if length != -1 || len(encoding) != 1 || encoding[0] != "chunked" {
+			t.Errorf("Content-Length %d, Transfer-Encoding %v, want chunked", length, encoding)
+		}
+	})
+}
+
+func TestRequestUploadSpecURL(t *testing.T) {
+	ctx := context.Background()
+	o := newUploadOptions([]UploadOption{WithUploadConflict(ConflictOverwrite)})
+
+	t.Run("account host", func(t *testing.T) {
+		fs := newFakeStorage(t)
+		spec, err := requestUploadSpec(ctx, "fo1", "report 2025.pdf", 42, o)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := "acme.sf-api.com/sf/v3/Items(fo1)/Upload?fileName=report+2025.pdf&fileSize=42&overwrite=true&responseFormat=json"
+		if got := fs.specs[0].Host + fs.specs[0].RequestURI(); got != want {
+			t.Errorf("upload specification requested from\n%s, want\n%s", got, want)
+		}
+		if spec.ChunkURI != "https://acme.sf-api.com/upload/chunk?id=1" {
+			t.Errorf("ChunkUri %q", spec.ChunkURI)
+		}
+	})
+
+	t.Run("storage zone", func(t *testing.T) {
+		fakeAPI(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(t, w, map[string]interface{}{
+				"Id":          "fo1",
+				"Redirection": map[string]string{"Uri": "https://zone.example.com/sf/v3/Items(fo1)"},
+			})
+		}))
+		var got string
+		fakeHost(t, "zone.example.com", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = r.Host + r.URL.RequestURI()
+			writeJSON(t, w, map[string]string{"ChunkUri": "https://zone.example.com/upload/chunk"})
+		}))
+
+		if _, err := requestUploadSpec(ctx, "fo1", "a.txt", 1, o); err != nil {
+			t.Fatal(err)
+		}
+
+		want := "zone.example.com/sf/v3/Items(fo1)/Upload?fileName=a.txt&fileSize=1&overwrite=true&responseFormat=json"
+		if got != want {
+			t.Errorf("upload specification requested from\n%s, want\n%s", got, want)
+		}
+	})
+}
+
+func TestUploadFile(t *testing.T) {
+	fs := newFakeStorage(t)
+
+	localPath := filepath.Join(t.TempDir(), "notes.txt")
+	if err := ioutil.WriteFile(localPath, []byte("hello, sharefile"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	modified := time.Date(2025, 3, 14, 15, 9, 26, 0, time.UTC)
+	if err := os.Chtimes(localPath, modified, modified); err != nil {
+		t.Fatal(err)
+	}
+
+	item, err := UploadFile(context.Background(), localPath, "fo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if item.ID != "fi-notes.txt" || item.Name != "notes.txt" || item.FileSizeBytes != 16 {
+		t.Errorf("UploadFile returned %+v", item)
+	}
+	if data, _ := fs.file("notes.txt"); string(data) != "hello, sharefile" {
+		t.Errorf("stored %q", data)
+	}
+	if got := fs.params["notes.txt"].Get("clientModifiedDateUTC"); got != "2025-03-14T15:09:26Z" {
+		t.Errorf("clientModifiedDateUTC %q", got)
+	}
+}
+
+func TestUploadClientTimes(t *testing.T) {
+	ctx := context.Background()
+	newFakeStorage(t)
+
+	local := time.FixedZone("UTC+2", 2*60*60)
+	created := time.Date(2024, 12, 31, 23, 30, 0, 0, local)
+	modified := time.Date(2025, 1, 1, 1, 2, 3, 456789000, local)
+
+	uploaded, err := Upload(ctx, "fo1", "times.txt", strings.NewReader("x"), 1, WithClientTimes(created, modified))
+	if err != nil {
+		t.Fatal(err)
+	}
+	item, err := GetItemByID(ctx, uploaded.ID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !item.ClientCreatedDate.Equal(created) {
+		t.Errorf("ClientCreatedDate %v, want %v", item.ClientCreatedDate, created)
+	}
+	if want := modified.Truncate(time.Second); !item.ClientModifiedDate.Equal(want) {
+		t.Errorf("ClientModifiedDate %v, want %v", item.ClientModifiedDate, want)
+	}
+}
+
+func TestPeekContentType(t *testing.T) {
+	for _, tt := range sniffTests {
+		t.Run(tt.name, func(t *testing.T) {
+			br := bufio.NewReader(strings.NewReader(tt.content))
+			got, err := peekContentType(tt.name, br)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("peekContentType = %q, want %q", got, tt.want)
+			}
+
+			// The sniffed bytes must still be there for the upload.
+			rest, err := ioutil.ReadAll(br)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(rest) != tt.content {
+				t.Errorf("reader left with %d bytes, want all %d", len(rest), len(tt.content))
+			}
+		})
+	}
+}
+
+func TestUploadFileSkipIfUnchanged(t *testing.T) {
+	ctx := context.Background()
+	localPath := filepath.Join(t.TempDir(), "data.csv")
+	write := func(t *testing.T, content string) {
+		if err := ioutil.WriteFile(localPath, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("unchanged", func(t *testing.T) {
+		fs := newFakeStorage(t)
+		write(t, "a,b\n1,2\n")
+		if _, err := UploadFile(ctx, localPath, "fo1"); err != nil {
+			t.Fatal(err)
+		}
+
+		item, err := UploadFile(ctx, localPath, "fo1", WithSkipIfUnchanged())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !item.Skipped || item.Name != "data.csv" {
+			t.Errorf("UploadFile returned %+v, want the existing file skipped", item)
+		}
+		if len(fs.specs) != 1 {
+			t.Errorf("%d uploads, want only the first", len(fs.specs))
+		}
+	})
+
+	t.Run("changed", func(t *testing.T) {
+		fs := newFakeStorage(t)
+		write(t, "a,b\n1,2\n")
+		if _, err := UploadFile(ctx, localPath, "fo1"); err != nil {
+			t.Fatal(err)
+		}
+		write(t, "a,b\n3,4\n")
+
+		item, err := UploadFile(ctx, localPath, "fo1", WithSkipIfUnchanged())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if item.Skipped || item.Name != "data.csv" {
+			t.Errorf("UploadFile returned %+v, want data.csv replaced", item)
+		}
+		if data, _ := fs.file("data.csv"); string(data) != "a,b\n3,4\n" {
+			t.Errorf("data.csv holds %q", data)
+		}
+	})
+
+	t.Run("changed with explicit policy", func(t *testing.T) {
+		fs := newFakeStorage(t)
+		write(t, "a,b\n1,2\n")
+		if _, err := UploadFile(ctx, localPath, "fo1"); err != nil {
+			t.Fatal(err)
+		}
+		write(t, "a,b\n3,4\n")
+
+		item, err := UploadFile(ctx, localPath, "fo1", WithSkipIfUnchanged(), WithUploadConflict(ConflictRename))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if item.Name == "data.csv" {
+			t.Errorf("ConflictRename upload stored as %q", item.Name)
+		}
+		if data, _ := fs.file("data.csv"); string(data) != "a,b\n1,2\n" {
+			t.Errorf("data.csv holds %q, want it left alone", data)
+		}
+	})
+}
+
+func TestThreadedUploadSpecExpiry(t *testing.T) {
+	fs := newFakeStorage(t)
+	fs.expireAfter = 3
+
+	content := "0123456789abcdefghij"
+	item, err := Upload(context.Background(), "fo1", "big.bin", strings.NewReader(content), int64(len(content)),
+		WithChunkSize(4), WithParallelChunks(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(fs.specs) != 2 {
+		t.Fatalf("%d upload specifications requested, want a renewal after the third chunk", len(fs.specs))
+	}
+
+	// Chunks confirmed on the first spec aren't sent again; the refused one and the rest go to the new spec.
+	var sent []string
+	for _, r := range fs.requests {
+		if r.URL.Path == "/upload/chunk" {
+			sent = append(sent, r.URL.Query().Get("id")+":"+r.URL.Query().Get("index"))
+		}
+	}
+	if want := "1:0 1:1 1:2 1:3 2:3 2:4"; strings.Join(sent, " ") != want {
+		t.Errorf("chunks sent as %s, want %s", strings.Join(sent, " "), want)
+	}
+
+	if data, _ := fs.file("big.bin"); string(data) != content {
+		t.Errorf("stored %q", data)
+	}
+	if len(fs.sizes) != 1 || item.Name != "big.bin" {
+		t.Errorf("upload stored %d files, returned %q", len(fs.sizes), item.Name)
+	}
+}
+
+func TestUploadedItemCount(t *testing.T) {
+	one := `{"error":false,"value":[{"id":"fi1","parentid":"fo1","filename":"a.txt","displayname":"a.txt","size":1}]}`
+	two := `{"error":false,"value":[{"id":"fi1","filename":"a.txt"},{"id":"fi2","filename":"a (1).txt"}]}`
+
+	item, err := uploadedItem(context.Background(), []byte(one), "fo1", "a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if item.ID != "fi1" || item.Name != "a.txt" {
+		t.Errorf("uploadedItem returned %+v", item)
+	}
+
+	if _, err := uploadedItem(context.Background(), []byte(two), "fo1", "a.txt"); err == nil {
+		t.Error("no error for an upload that registered two files")
+	}
+}
+
+func TestRawPostUploadURL(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.RawQuery
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		chunkURI string
+		want     string
+	}{
+		{srv.URL + "/upload", "raw=true"},
+		{srv.URL + "/upload?id=1", "id=1&raw=true"},
+		{srv.URL + "/upload?id=1&raw=true", "id=1&raw=true"},
+	}
+	for _, tt := range tests {
+		if _, err := rawPostUpload(context.Background(), tt.chunkURI, "text/plain", strings.NewReader("x"), 1); err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%s posted with query %q, want %q", tt.chunkURI, got, tt.want)
+		}
+	}
+}
+
+func TestRawUpload(t *testing.T) {
+	fs := newFakeStorage(t)
+
+	item, err := Upload(context.Background(), "fo1", "raw.bin", strings.NewReader("raw bytes"), 9, WithRawUpload())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := fs.file(item.Name); string(data) != "raw bytes" {
+		t.Errorf("stored %q", data)
+	}
+	if raw := fs.params["raw.bin"].Get("raw"); raw != "true" {
+		t.Errorf("upload specification requested with raw=%q", raw)
+	}
+}
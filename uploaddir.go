@@ -0,0 +1,247 @@
+package go-sharefile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Number of files uploaded at once when WithConcurrency isn't given.
+const defaultUploadConcurrency = 4
+
+// WithConcurrency sets how many files UploadDirectory uploads at once. The default is 4.
+func WithConcurrency(n int) UploadOption {
+	return func(o *uploadOptions) {
+		o.concurrency = n
+	}
+}
+
+// WithFollowSymlinks makes UploadDirectory follow symbolic links, uploading the files and directories they point at.
+// By default links are skipped.
+func WithFollowSymlinks() UploadOption {
+	return func(o *uploadOptions) {
+		o.followSymlinks = true
+	}
+}
+
+// WithSkip makes UploadDirectory leave out every file and directory for which skip returns true, such as hidden
+// files. The path is relative to the directory being uploaded.
+func WithSkip(skip func(path string, d fs.DirEntry) bool) UploadOption {
+	return func(o *uploadOptions) {
+		o.skip = skip
+	}
+}
+
+// Returns the number of files uploaded at once, internal package use.
+func (o *uploadOptions) workers() int {
+	if o.concurrency <= 0 {
+		return defaultUploadConcurrency
+	}
+	return o.concurrency
+}
+
+// UploadReport is the outcome of UploadDirectory. Paths are relative to the uploaded directory.
+type UploadReport struct {
+	Uploaded []string
+	Skipped  []string
+	Failed   map[string]error
+}
+
+// UploadDirectory uploads the files below a local directory into a remote folder, creating the matching folder
+// structure. A failed file or folder doesn't stop the others: the report lists what happened to every path, and the
+// returned error joins the failures. Once ctx is done no further files are started, and the one waiting for a free
+// upload reports the context's error.
+func UploadDirectory(ctx context.Context, localDir, remoteFolderID string, opts ...UploadOption) (*UploadReport, error) {
+	o := newUploadOptions(opts)
+	u := &dirUpload{
+		ctx:     ctx,
+		opts:    opts,
+		o:       o,
+		sem:     make(chan struct{}, o.workers()),
+		visited: map[string]bool{},
+		report:  &UploadReport{Failed: map[string]error{}},
+	}
+
+	u.walk(localDir, ".", remoteFolderID)
+	u.wg.Wait()
+
+	sort.Strings(u.report.Uploaded)
+	sort.Strings(u.report.Skipped)
+
+	paths := make([]string, 0, len(u.report.Failed))
+	for p := range u.report.Failed {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	errs := make([]error, 0, len(paths))
+	for _, p := range paths {
+		errs = append(errs, fmt.Errorf("%s: %w", p, u.report.Failed[p]))
+	}
+
+	return u.report, errors.Join(errs...)
+}
+
+// State of one UploadDirectory call, internal package use.
+type dirUpload struct {
+	ctx     context.Context
+	opts    []UploadOption
+	o       *uploadOptions
+	sem     chan struct{}
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	visited map[string]bool
+	report  *UploadReport
+}
+
+// Records the outcome for a path, internal package use.
+func (u *dirUpload) record(rel string, err error, skipped bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	switch {
+	case err != nil:
+		u.report.Failed[rel] = err
+	case skipped:
+		u.report.Skipped = append(u.report.Skipped, rel)
+	default:
+		u.report.Uploaded = append(u.report.Uploaded, rel)
+	}
+}
+
+// Walks one local directory, creating remote folders as it goes and queueing its files for upload, internal package
+// use.
+func (u *dirUpload) walk(dir, rel, folderID string) {
+	// Following links can lead back to a directory already uploaded.
+	if real, err := filepath.EvalSymlinks(dir); err == nil {
+		if u.visited[real] {
+			return
+		}
+		u.visited[real] = true
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		u.record(rel, err, false)
+		return
+	}
+
+	for _, d := range entries {
+		if u.ctx.Err() != nil {
+			return
+		}
+
+		path := filepath.Join(dir, d.Name())
+		childRel := filepath.Join(rel, d.Name())
+
+		if u.o.skip != nil && u.o.skip(childRel, d) {
+			u.record(childRel, nil, true)
+			continue
+		}
+
+		isDir := d.IsDir()
+		if d.Type()&fs.ModeSymlink != 0 {
+			if !u.o.followSymlinks {
+				u.record(childRel, nil, true)
+				continue
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				u.record(childRel, err, false)
+				continue
+			}
+			isDir = info.IsDir()
+		}
+
+		if isDir {
+			folder, _, err := FindOrCreateFolder(u.ctx, folderID, d.Name(), "")
+			if err != nil {
+				u.record(childRel, err, false)
+				continue
+			}
+			u.walk(path, childRel, folder.ID)
+			continue
+		}
+
+		select {
+		case u.sem <- struct{}{}:
+		case <-u.ctx.Done():
+			u.record(childRel, u.ctx.Err(), false)
+			return
+		}
+		u.wg.Add(1)
+		go func(path, rel string) {
+			defer u.wg.Done()
+			defer func() { <-u.sem }()
+
+			item, err := UploadFile(u.ctx, path, folderID, u.opts...)
+			u.record(rel, err, item != nil && item.Skipped)
+		}(path, childRel)
+	}
+}
+
+// UploadResult is the outcome of one file of UploadFiles.
+type UploadResult struct {
+	Path string
+	Item *Item
+	// Hash is the MD5 hash of the uploaded file, verified against the stored one when WithChecksum is given.
+	Hash     string
+	Duration time.Duration
+	Err      error
+}
+
+// UploadFiles uploads several local files to a folder, at most concurrency at a time, and returns a result for every
+// path in the order given. The limit covers each upload as a whole, including its upload specification request, so it
+// also bounds the load on the API. Once ctx is done no further files are started; those already running finish, and
+// the rest report the context's error. The returned error joins the failures.
+func UploadFiles(ctx context.Context, folderID string, paths []string, concurrency int, opts ...UploadOption) ([]UploadResult, error) {
+	if concurrency <= 0 {
+		concurrency = defaultUploadConcurrency
+	}
+
+	results := make([]UploadResult, len(paths))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, path := range paths {
+		results[i].Path = path
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			results[i].Err = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(r *UploadResult) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			start := time.Now()
+			r.Item, r.Err = UploadFile(ctx, r.Path, folderID, opts...)
+			r.Duration = time.Since(start)
+			if r.Item != nil {
+				r.Hash = r.Item.Hash
+			}
+		}(&results[i])
+	}
+	wg.Wait()
+
+	var errs []error
+	for _, r := range results {
+		if r.Err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", r.Path, r.Err))
+		}
+	}
+
+	return results, errors.Join(errs...)
+}
@@ -0,0 +1,741 @@
+package go-sharefile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrUserExists is matched by the error returned when creating a user whose email address is already in use.
+var ErrUserExists = errors.New("sharefile: user already exists")
+
+// UserExistsError is returned when creating a user whose email address is already in use. ID is the existing user's,
+// when it could be looked up.
+type UserExistsError struct {
+	Email string
+	ID    string
+}
+
+func (e *UserExistsError) Error() string {
+	return fmt.Sprintf("sharefile: user %s already exists", e.Email)
+}
+
+// Is reports whether target is ErrUserExists.
+func (e *UserExistsError) Is(target error) bool {
+	return target == ErrUserExists
+}
+
+// User is a ShareFile user, either an employee of the account or a client. Fields left out of the response stay at
+// their zero value.
+//
+// LastAnyLogin is the user's last sign-in through any app. Security and HomeFolder are only filled in when expanded.
+type User struct {
+	ID               string        `json:"Id"`
+	Email            string        `json:"Email"`
+	FirstName        string        `json:"FirstName"`
+	LastName         string        `json:"LastName"`
+	FullName         string        `json:"FullName"`
+	Company          string        `json:"Company"`
+	CreatedDate      time.Time     `json:"CreatedDate"`
+	IsEmployee       bool          `json:"IsEmployee"`
+	IsDisabled       bool          `json:"IsDisabled"`
+	Roles            []string      `json:"Roles"`
+	LastAnyLogin     time.Time     `json:"LastAnyLogin"`
+	TotalSharedFiles int           `json:"TotalSharedFiles"`
+	DefaultZone      *Zone         `json:"DefaultZone"`
+	Security         *UserSecurity `json:"Security"`
+	HomeFolder       *Item         `json:"HomeFolder"`
+	// StorageQuotaLimitGB is the user's storage quota in gigabytes, or UnlimitedQuota. It is nil when the response
+	// doesn't include it, as for client users.
+	StorageQuotaLimitGB *int `json:"StorageQuotaLimitGB"`
+}
+
+// UnlimitedQuota is the storage quota of users whose storage isn't capped.
+const UnlimitedQuota = -1
+
+// UserSecurity is the sign-in state of a user. LockExpires is when a locked account unlocks by itself, and
+// PasswordExpires when the password must next be changed, unless PasswordNeverExpires is set.
+type UserSecurity struct {
+	IsLocked             bool      `json:"IsLocked"`
+	LockExpires          time.Time `json:"LockExpires"`
+	FailedLoginCount     int       `json:"FailedLoginCount"`
+	LastWebAppLogin      time.Time `json:"LastWebAppLogin"`
+	PasswordExpires      time.Time `json:"PasswordExpires"`
+	PasswordNeverExpires bool      `json:"PasswordNeverExpires"`
+	ForceChangePassword  bool      `json:"ForceChangePassword"`
+	// TwoFactorEnforced reports whether the user must sign in with two-step verification.
+	TwoFactorEnforced bool `json:"IsTwoFactorEnforced"`
+}
+
+// GetUserSecurity returns the sign-in state of a user. It needs administrator rights, failing with an error wrapping
+// ErrForbidden otherwise.
+func GetUserSecurity(ctx context.Context, userID string) (*UserSecurity, error) {
+	var security UserSecurity
+	if err := call(ctx, "GET", fmt.Sprintf("/sf/v3/Users(%s)/Security", userID), nil, &security); err != nil {
+		return nil, err
+	}
+
+	return &security, nil
+}
+
+// UnlockUser clears the lockout of a user who has had too many failed sign-ins. It needs administrator rights,
+// failing with an error wrapping ErrForbidden otherwise.
+func UnlockUser(ctx context.Context, userID string) error {
+	return call(ctx, "POST", fmt.Sprintf("/sf/v3/Users(%s)/Unlock", userID), nil, nil)
+}
+
+// GetUser returns a user. The query may be nil; expand "Security" and "HomeFolder" to fill in those fields in the same
+// call. Looking up another user needs administrator rights, failing with an error wrapping ErrForbidden otherwise.
+func GetUser(ctx context.Context, userID string, q *Query) (*User, error) {
+	var user User
+	if err := call(ctx, "GET", withQuery(fmt.Sprintf("/sf/v3/Users(%s)", userID), q), nil, &user); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// Collection response wrapping a list of users, internal package use.
+type userFeed struct {
+	Count int    `json:"odata.count"`
+	Users []User `json:"value"`
+}
+
+// UserIterator pages through a list of users, fetching a page at a time as Next is called. It is used like
+// ChildIterator.
+type UserIterator struct {
+	ctx     context.Context
+	uriPath string
+	opts    ListOptions
+	page    []User
+	index   int
+	skip    int
+	done    bool
+	err     error
+}
+
+// Returns an iterator over the users listed at uriPath, internal package use.
+func newUserIterator(ctx context.Context, uriPath string, opts ListOptions) *UserIterator {
+	return &UserIterator{
+		ctx:     ctx,
+		uriPath: uriPath,
+		opts:    opts,
+		index:   -1,
+	}
+}
+
+// NewClientIterator returns an iterator over the client users of the account.
+func NewClientIterator(ctx context.Context, opts ListOptions) *UserIterator {
+	return newUserIterator(ctx, "/sf/v3/Accounts/Clients", opts)
+}
+
+// NewEmployeeIterator returns an iterator over the employee users of the account.
+func NewEmployeeIterator(ctx context.Context, opts ListOptions) *UserIterator {
+	return newUserIterator(ctx, "/sf/v3/Accounts/Employees", opts)
+}
+
+// Next advances to the next user, fetching the next page when needed. It returns false when there are no more users
+// or an error occurred.
+func (it *UserIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	it.index++
+	if it.index < len(it.page) {
+		return true
+	}
+	if it.done {
+		return false
+	}
+
+	// User listings have no sortable item fields; only Id keeps the pages stable.
+	if it.opts.SortBy != "" {
+		it.err = fmt.Errorf("sharefile: cannot sort users by %q", it.opts.SortBy)
+		return false
+	}
+	size := it.opts.pageSize()
+	q := it.opts.Query.clone().OrderBy("Id", false).Top(size).Skip(it.skip)
+
+	var feed userFeed
+	if err := call(it.ctx, "GET", withQuery(it.uriPath, q), nil, &feed); err != nil {
+		it.err = err
+		return false
+	}
+
+	it.page = feed.Users
+	it.index = 0
+	it.skip += len(feed.Users)
+	it.done = len(feed.Users) < size
+
+	return len(it.page) > 0
+}
+
+// User returns the current user.
+func (it *UserIterator) User() *User {
+	return &it.page[it.index]
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *UserIterator) Err() error {
+	return it.err
+}
+
+// Collects every user of an iterator, internal package use.
+func (it *UserIterator) all() ([]User, error) {
+	users := []User{}
+	for it.Next() {
+		users = append(users, *it.User())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// Clients returns every client user of the account, fetching as many pages as needed. Use NewClientIterator to
+// process large accounts a page at a time instead. Sorting isn't supported.
+func Clients(ctx context.Context, opts ListOptions) ([]User, error) {
+	return NewClientIterator(ctx, opts).all()
+}
+
+// Employees returns every employee user of the account, fetching as many pages as needed. Use NewEmployeeIterator to
+// process large accounts a page at a time instead. Sorting isn't supported.
+func Employees(ctx context.Context, opts ListOptions) ([]User, error) {
+	return NewEmployeeIterator(ctx, opts).all()
+}
+
+// UserByEmail returns the user, employee or client, with the given email address. A missing user is an error wrapping
+// ErrNotFound. Disabled users are returned too, with IsDisabled set.
+func UserByEmail(ctx context.Context, email string) (*User, error) {
+	// QueryEscape encodes "+" and non-ASCII characters, which would otherwise arrive as a space or be mangled.
+	uriPath := "/sf/v3/Users?emailaddress=" + url.QueryEscape(email)
+
+	var user User
+	err := call(ctx, "GET", uriPath, nil, &user)
+	if errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("sharefile: user %s: %w", email, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user.ID == "" {
+		return nil, fmt.Errorf("sharefile: user %s: %w", email, ErrNotFound)
+	}
+
+	return &user, nil
+}
+
+// ClientUser describes a client user to create with CreateClient.
+type ClientUser struct {
+	Email     string
+	FirstName string
+	LastName  string
+	Company   string
+	// Password is optional. Users created without one set their own through the activation link of the welcome
+	// email, so pass WithWelcomeEmail.
+	Password          string
+	CanResetPassword  bool
+	CanViewMySettings bool
+}
+
+// EmployeeUser describes an employee user to create with CreateEmployee.
+type EmployeeUser struct {
+	Email     string
+	FirstName string
+	LastName  string
+	Company   string
+	// StorageQuotaLimitGB caps the user's storage, in gigabytes, or is UnlimitedQuota. Nil leaves the account's
+	// default.
+	StorageQuotaLimitGB *int
+	CanCreateFolders    bool
+	IsAdministrator     bool
+}
+
+// Struct for use in employee POST activities
+type employeeBody struct {
+	Email               string
+	FirstName           string
+	LastName            string
+	Company             string
+	StorageQuotaLimitGB *int `json:",omitempty"`
+	CanCreateFolders    bool
+	IsAdministrator     bool
+}
+
+// ErrNoLicense is returned when an employee can't be created or upgraded because every employee license of the
+// account is in use.
+var ErrNoLicense = errors.New("sharefile: no employee license available")
+
+// CreateEmployee creates an employee user in the account, taking one of its licenses, and returns it. A duplicate
+// email address fails as for CreateClient; running out of licenses fails with an error wrapping ErrNoLicense.
+func CreateEmployee(ctx context.Context, e EmployeeUser, opts ...UserOption) (*User, error) {
+	employee := employeeBody{
+		Email:               e.Email,
+		FirstName:           e.FirstName,
+		LastName:            e.LastName,
+		Company:             e.Company,
+		StorageQuotaLimitGB: e.StorageQuotaLimitGB,
+		CanCreateFolders:    e.CanCreateFolders,
+		IsAdministrator:     e.IsAdministrator,
+	}
+
+	o := newUserOptions(opts)
+	uriPath := "/sf/v3/Users/AccountUser"
+	if params := o.params(); len(params) > 0 {
+		uriPath += "?" + params.Encode()
+	}
+
+	var created User
+	if err := call(ctx, "POST", uriPath, employee, &created); err != nil {
+		if licenseExhausted(err) {
+			return nil, fmt.Errorf("sharefile: employee %s not created: %w", e.Email, ErrNoLicense)
+		}
+		return nil, userExistsError(ctx, e.Email, err)
+	}
+
+	return &created, o.sendWelcome(ctx, &created)
+}
+
+// Reports whether the API refused a request for lack of employee licenses, internal package use.
+func licenseExhausted(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return (apiErr.StatusCode == http.StatusBadRequest || apiErr.StatusCode == http.StatusForbidden) &&
+		strings.Contains(strings.ToLower(apiErr.Message), "license")
+}
+
+// UserOption configures the creation of a user.
+type UserOption func(*userOptions)
+
+// Options collected from UserOption values, internal package use.
+type userOptions struct {
+	notify         bool
+	addShared      bool
+	welcomeMessage string
+}
+
+// WithWelcomeEmail sends the new user the welcome email with their activation link.
+func WithWelcomeEmail() UserOption {
+	return func(o *userOptions) {
+		o.notify = true
+	}
+}
+
+// WithWelcomeMessage sends the new user the welcome email with a custom message added, through ResendWelcomeEmail
+// once the user exists.
+func WithWelcomeMessage(msg string) UserOption {
+	return func(o *userOptions) {
+		o.welcomeMessage = msg
+	}
+}
+
+// WithAddShared adds the new user to the account's shared address book.
+func WithAddShared() UserOption {
+	return func(o *userOptions) {
+		o.addShared = true
+	}
+}
+
+// Applies user options over the defaults, internal package use.
+func newUserOptions(opts []UserOption) *userOptions {
+	o := &userOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Returns the query parameters for the user creation request, internal package use.
+func (o *userOptions) params() url.Values {
+	params := url.Values{}
+	if o.addShared {
+		params.Set("addshared", "true")
+	}
+	// A welcome message goes out separately, so the plain welcome email isn't sent as well.
+	if o.notify && o.welcomeMessage == "" {
+		params.Set("notify", "true")
+	}
+	return params
+}
+
+// Turns the API's refusal of a duplicate email address into a *UserExistsError, internal package use.
+func userExistsError(ctx context.Context, email string, err error) error {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+	// Depending on the account, duplicates are refused with a conflict or a bad request naming the address.
+	duplicate := apiErr.StatusCode == http.StatusConflict ||
+		apiErr.StatusCode == http.StatusBadRequest && strings.Contains(strings.ToLower(apiErr.Message), "already exist")
+	if !duplicate {
+		return err
+	}
+
+	exists := &UserExistsError{Email: email}
+	if existing, err := UserByEmail(ctx, email); err == nil {
+		exists.ID = existing.ID
+	}
+
+	return exists
+}
+
+// UserUpdate holds the fields UpdateUser changes. Nil fields are left as they are.
+type UserUpdate struct {
+	// Email changes the address the user signs in with. ShareFile refuses addresses already in use by another user.
+	Email     *string `json:",omitempty"`
+	FirstName *string `json:",omitempty"`
+	LastName  *string `json:",omitempty"`
+	Company   *string `json:",omitempty"`
+}
+
+// UpdateUser updates the name, company or email address of a user and returns the updated user.
+func UpdateUser(ctx context.Context, userID string, update UserUpdate) (*User, error) {
+	var user User
+	err := call(ctx, "PATCH", fmt.Sprintf("/sf/v3/Users(%s)", userID), update, &user)
+
+	var apiErr *APIError
+	if update.Email != nil && errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest {
+		// Only an administrator can change another user's address, and never to one that is taken.
+		return nil, fmt.Errorf("sharefile: email of user %s not changed to %s, which may be in use by another user or need an administrator to change: %w", userID, *update.Email, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// DeleteUserOptions controls DeleteUser.
+type DeleteUserOptions struct {
+	// Completely removes the user entirely rather than only from the account, for client users also belonging to
+	// other accounts.
+	Completely bool
+	// ItemsReassignTo and GroupsReassignTo are the IDs of the users taking over the deleted user's items and the
+	// groups they own. Users owning shared folders can't be deleted without ItemsReassignTo.
+	ItemsReassignTo  string
+	GroupsReassignTo string
+}
+
+// DeleteUser deletes a user. Use UserOwnedItems beforehand to see which items move to ItemsReassignTo.
+func DeleteUser(ctx context.Context, userID string, opts DeleteUserOptions) error {
+	params := url.Values{}
+	if opts.Completely {
+		params.Set("completely", "true")
+	}
+	if opts.ItemsReassignTo != "" {
+		params.Set("itemsReassignTo", opts.ItemsReassignTo)
+	}
+	if opts.GroupsReassignTo != "" {
+		params.Set("groupsReassignTo", opts.GroupsReassignTo)
+	}
+
+	uriPath := fmt.Sprintf("/sf/v3/Users(%s)", userID)
+	if len(params) > 0 {
+		uriPath += "?" + params.Encode()
+	}
+
+	err := call(ctx, "DELETE", uriPath, nil, nil)
+
+	var apiErr *APIError
+	if opts.ItemsReassignTo == "" && errors.As(err, &apiErr) &&
+		(apiErr.StatusCode == http.StatusBadRequest || apiErr.StatusCode == http.StatusConflict) {
+		return fmt.Errorf("sharefile: user %s not deleted, they may own items that need reassigning with ItemsReassignTo: %w", userID, err)
+	}
+
+	return err
+}
+
+// UserOwnedItems returns the folders a user owns: the contents of their home folder and the shared folders they
+// created. These are the items DeleteUser hands over to ItemsReassignTo.
+func UserOwnedItems(ctx context.Context, userID string) ([]Item, error) {
+	owned := []Item{}
+
+	// Client users have no home folder.
+	home, err := UserHomeFolder(ctx, userID)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	if home != nil {
+		it := NewChildIterator(ctx, home.ID, ListOptions{})
+		for it.Next() {
+			owned = append(owned, *it.Item())
+		}
+		if err := it.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	shared, err := UserSharedFolders(ctx, userID, ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range shared {
+		if f.Creator != nil && f.Creator.ID == userID {
+			owned = append(owned, f)
+		}
+	}
+
+	return owned, nil
+}
+
+// ErrAlreadyActivated is returned by ResendWelcomeEmail for users who have already activated their account.
+var ErrAlreadyActivated = errors.New("sharefile: user already activated")
+
+// Struct for use in welcome email POST activities
+type welcomeBody struct {
+	CustomMessage string `json:",omitempty"`
+}
+
+// ResendWelcomeEmail sends a user the welcome email with their activation link again, with an optional custom
+// message. Users who have already activated their account get an error wrapping ErrAlreadyActivated.
+func ResendWelcomeEmail(ctx context.Context, userID, customMessage string) error {
+	body := welcomeBody{CustomMessage: customMessage}
+	err := call(ctx, "POST", fmt.Sprintf("/sf/v3/Users(%s)/ResendWelcome", userID), body, nil)
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest &&
+		strings.Contains(strings.ToLower(apiErr.Message), "activ") {
+		return fmt.Errorf("sharefile: user %s: %w", userID, ErrAlreadyActivated)
+	}
+
+	return err
+}
+
+// Sends the welcome message of a newly created user, if one was asked for, internal package use.
+func (o *userOptions) sendWelcome(ctx context.Context, user *User) error {
+	if o.welcomeMessage == "" {
+		return nil
+	}
+	if err := ResendWelcomeEmail(ctx, user.ID, o.welcomeMessage); err != nil {
+		return fmt.Errorf("sharefile: user %s created but welcome email not sent: %w", user.ID, err)
+	}
+	return nil
+}
+
+// Struct for use in user disable PATCH activities
+type userDisabledBody struct {
+	IsDisabled bool
+}
+
+// SetUserDisabled disables a user, blocking their access without deleting them, or enables them again. Setting the
+// state a user is already in is not an error.
+func SetUserDisabled(ctx context.Context, userID string, disabled bool) error {
+	return call(ctx, "PATCH", fmt.Sprintf("/sf/v3/Users(%s)", userID), userDisabledBody{IsDisabled: disabled}, nil)
+}
+
+// EmployeeUpgrade holds the employee settings given to a client user by PromoteClientToEmployee.
+type EmployeeUpgrade struct {
+	// StorageQuotaLimitGB caps the user's storage, in gigabytes, or is UnlimitedQuota. Nil leaves the account's
+	// default.
+	StorageQuotaLimitGB *int `json:",omitempty"`
+	CanCreateFolders    bool
+	IsAdministrator     bool
+}
+
+// PromoteClientToEmployee turns a client user into an employee, taking one of the account's licenses. The user keeps
+// their ID and the folders shared with them. Running out of licenses fails with an error wrapping ErrNoLicense.
+func PromoteClientToEmployee(ctx context.Context, userID string, upgrade EmployeeUpgrade) (*User, error) {
+	var user User
+	if err := call(ctx, "POST", fmt.Sprintf("/sf/v3/Users(%s)/Upgrade", userID), upgrade, &user); err != nil {
+		if licenseExhausted(err) {
+			return nil, fmt.Errorf("sharefile: user %s not promoted: %w", userID, ErrNoLicense)
+		}
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// DemoteEmployeeToClient turns an employee into a client user, freeing their license. Employees who own items or
+// administer the account can't be demoted; the API refuses them with a bad request.
+func DemoteEmployeeToClient(ctx context.Context, userID string) (*User, error) {
+	var user User
+	if err := call(ctx, "POST", fmt.Sprintf("/sf/v3/Users(%s)/Downgrade", userID), nil, &user); err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// UserHomeFolder returns the home folder of a user, their "My Files & Folders", which can be used with Children,
+// Upload and the other item calls. Users without one, such as most clients, get an error wrapping ErrNotFound.
+func UserHomeFolder(ctx context.Context, userID string) (*Item, error) {
+	return userFolder(ctx, userID, "HomeFolder")
+}
+
+// UserFileBox returns the file box of a user, the folder holding files sent to them. Users without one get an error
+// wrapping ErrNotFound.
+func UserFileBox(ctx context.Context, userID string) (*Item, error) {
+	return userFolder(ctx, userID, "FileBox")
+}
+
+// Returns one of the special folders of a user, internal package use.
+func userFolder(ctx context.Context, userID, resource string) (*Item, error) {
+	var folder Item
+	err := call(ctx, "GET", fmt.Sprintf("/sf/v3/Users(%s)/%s", userID, resource), nil, &folder)
+	// Users without the folder get an empty response rather than a 404.
+	if err == io.EOF || err == nil && folder.ID == "" {
+		err = ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("sharefile: %s of user %s: %w", resource, userID, err)
+	}
+
+	return &folder, nil
+}
+
+// NewSharedFolderIterator returns an iterator over the shared folders a user can access. It is a ChildIterator, used
+// the same way, and its items are those of UserSharedFolders.
+func NewSharedFolderIterator(ctx context.Context, userID string, opts ListOptions) *ChildIterator {
+	it := NewChildIterator(ctx, "", opts)
+	it.list = func(ctx context.Context, q *Query) (*itemFeed, error) {
+		var feed itemFeed
+		q = q.Expand("Creator", "Parent")
+		err := call(ctx, "GET", withQuery(fmt.Sprintf("/sf/v3/Users(%s)/AllSharedFolders", userID), q), nil, &feed)
+		return &feed, err
+	}
+	return it
+}
+
+// UserSharedFolders returns every shared folder a user can access, fetching as many pages as needed. Each folder has
+// its Creator, the owner who shared it, and its Parent filled in; Breadcrumbs and FullPath give the rest of the path
+// through which access was granted. Use NewSharedFolderIterator to go through them a page at a time instead.
+func UserSharedFolders(ctx context.Context, userID string, opts ListOptions) ([]Item, error) {
+	it := NewSharedFolderIterator(ctx, userID, opts)
+
+	folders := []Item{}
+	for it.Next() {
+		folders = append(folders, *it.Item())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	return folders, nil
+}
+
+// Contact is an entry of the account's address book. ID is the principal ID of the user or group behind the contact,
+// which share and access calls take, and is empty for addresses that aren't users yet. IsEmployee tells employees from
+// client users.
+type Contact struct {
+	ID         string `json:"Id"`
+	Email      string `json:"Email"`
+	Name       string `json:"Name"`
+	Company    string `json:"Company"`
+	IsEmployee bool   `json:"IsEmployee"`
+}
+
+// IsUser reports whether the contact is an existing user.
+func (c *Contact) IsUser() bool {
+	return c.ID != ""
+}
+
+// Collection response wrapping a list of contacts, internal package use.
+type contactFeed struct {
+	Contacts []Contact `json:"value"`
+}
+
+// Contacts returns the entries of the account's shared address book whose name, email or company contain query, or
+// every entry when query is empty, fetching as many pages as needed. Sorting isn't supported.
+func Contacts(ctx context.Context, query string, opts ListOptions) ([]Contact, error) {
+	uriPath := "/sf/v3/Accounts/AddressBook?type=shared"
+	if query != "" {
+		uriPath += "&searchTerm=" + url.QueryEscape(query)
+	}
+
+	contacts := []Contact{}
+	err := opts.pageAll("contacts", func(q *Query) (int, error) {
+		var feed contactFeed
+		if err := call(ctx, "GET", withQuery(uriPath, q), nil, &feed); err != nil {
+			return 0, err
+		}
+		contacts = append(contacts, feed.Contacts...)
+		return len(feed.Contacts), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return contacts, nil
+}
+
+// UserPreferences are the notification and regional settings of a user. EmailInterval is how often, in minutes,
+// notifications are batched into one email, with zero sending them immediately.
+type UserPreferences struct {
+	EmailInterval  int    `json:"EmailInterval"`
+	TimeZone       string `json:"TimeZone"`
+	Locale         string `json:"Locale"`
+	NotifyDownload bool   `json:"DefaultDownloadNotify"`
+	NotifyUpload   bool   `json:"DefaultUploadNotify"`
+}
+
+// UserPreferencesUpdate holds the preferences UpdateUserPreferences changes. Nil fields are left as they are.
+type UserPreferencesUpdate struct {
+	EmailInterval  *int    `json:",omitempty"`
+	TimeZone       *string `json:",omitempty"`
+	Locale         *string `json:",omitempty"`
+	NotifyDownload *bool   `json:"DefaultDownloadNotify,omitempty"`
+	NotifyUpload   *bool   `json:"DefaultUploadNotify,omitempty"`
+}
+
+// GetUserPreferences returns the preferences of a user.
+func GetUserPreferences(ctx context.Context, userID string) (*UserPreferences, error) {
+	var prefs UserPreferences
+	if err := call(ctx, "GET", fmt.Sprintf("/sf/v3/Users(%s)/Preferences", userID), nil, &prefs); err != nil {
+		return nil, err
+	}
+
+	return &prefs, nil
+}
+
+// UpdateUserPreferences changes some of the preferences of a user.
+func UpdateUserPreferences(ctx context.Context, userID string, update UserPreferencesUpdate) error {
+	return call(ctx, "PATCH", fmt.Sprintf("/sf/v3/Users(%s)/Preferences", userID), update, nil)
+}
+
+// Struct for use in user quota PATCH activities
+type userQuotaBody struct {
+	StorageQuotaLimitGB int
+}
+
+// SetUserQuota sets the storage quota of an employee, in gigabytes; pass UnlimitedQuota to remove the cap. Client
+// users have no quota of their own and are refused.
+func SetUserQuota(ctx context.Context, userID string, gigabytes int) error {
+	if gigabytes < UnlimitedQuota {
+		return fmt.Errorf("sharefile: invalid storage quota %d GB", gigabytes)
+	}
+
+	user, err := GetUser(ctx, userID, NewQuery().Select("Id", "IsEmployee"))
+	if err != nil {
+		return err
+	}
+	if !user.IsEmployee {
+		return fmt.Errorf("sharefile: user %s is a client, which has no storage quota", userID)
+	}
+
+	return call(ctx, "PATCH", fmt.Sprintf("/sf/v3/Users(%s)", userID), userQuotaBody{StorageQuotaLimitGB: gigabytes}, nil)
+}
+
+// Storage information of a user, internal package use.
+type userInfo struct {
+	DiskSpaceUsed int64 `json:"DiskSpaceUsed"`
+}
+
+// UserUsage returns the number of bytes of storage a user takes up. Compare it with the user's StorageQuotaLimitGB,
+// in gigabytes of 1<<30 bytes, to see how close they are to their quota.
+func UserUsage(ctx context.Context, userID string) (int64, error) {
+	var info userInfo
+	if err := call(ctx, "GET", fmt.Sprintf("/sf/v3/Users(%s)/Info", userID), nil, &info); err != nil {
+		return 0, err
+	}
+
+	return info.DiskSpaceUsed, nil
+}
@@ -0,0 +1,171 @@
+package go-sharefile
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestUpdateUserCompanyOnly(t *testing.T) {
+	var body map[string]json.RawMessage
+	fakeAPI(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PATCH" || r.URL.Path != "/sf/v3/Users(u1)" {
+			t.Errorf("%s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		writeJSON(t, w, map[string]string{"Id": "u1", "Company": "Initech"})
+	}))
+
+	company := "Initech"
+	user, err := UpdateUser(context.Background(), "u1", UserUpdate{Company: &company})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(body) != 1 || string(body["Company"]) != `"Initech"` {
+		t.Errorf("PATCH body %v, want only Company", body)
+	}
+	if user.Company != "Initech" {
+		t.Errorf("UpdateUser returned company %q", user.Company)
+	}
+}
+
+func TestUpdateUserEmailRefused(t *testing.T) {
+	fakeAPI(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+
+	email := "taken@example.com"
+	_, err := UpdateUser(context.Background(), "u1", UserUpdate{Email: &email})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("UpdateUser returned %v, want the API's refusal", err)
+	}
+}
+
+func TestSetUserDisabled(t *testing.T) {
+	// The fake keeps one employee, applying each PATCH to it the way the API does.
+	var mu sync.Mutex
+	user := map[string]interface{}{"Id": "u1", "Email": "pat@example.com", "IsEmployee": true, "IsDisabled": false}
+	fakeAPI(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.Method == "PATCH" && r.URL.Path == "/sf/v3/Users(u1)":
+			var body map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Error(err)
+			}
+			if _, ok := body["IsDisabled"]; !ok {
+				t.Errorf("PATCH body %v has no IsDisabled", body)
+			}
+			for k, v := range body {
+				user[k] = v
+			}
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Path == "/sf/v3/Users(u1)":
+			writeJSON(t, w, user)
+		case r.URL.Path == "/sf/v3/Accounts/Employees":
+			writeJSON(t, w, map[string]interface{}{"value": []interface{}{user}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+
+	ctx := context.Background()
+	check := func(want bool) {
+		t.Helper()
+		got, err := GetUser(ctx, "u1", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.IsDisabled != want {
+			t.Errorf("GetUser IsDisabled = %v, want %v", got.IsDisabled, want)
+		}
+		listed, err := Employees(ctx, ListOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(listed) != 1 || listed[0].IsDisabled != want {
+			t.Errorf("Employees lists %+v, want IsDisabled %v", listed, want)
+		}
+	}
+
+	for _, disabled := range []bool{true, true, false, false} {
+		if err := SetUserDisabled(ctx, "u1", disabled); err != nil {
+			t.Fatalf("SetUserDisabled(%v): %v", disabled, err)
+		}
+		check(disabled)
+	}
+}
+
+func TestUserPreferencesRoundTrip(t *testing.T) {
+	initial := UserPreferences{EmailInterval: 60, TimeZone: "GMT Standard Time", Locale: "en-GB", NotifyDownload: true}
+
+	interval, zone, locale, yes, no := 0, "Pacific Standard Time", "de-DE", true, false
+	tests := []struct {
+		name   string
+		update UserPreferencesUpdate
+		key    string
+		want   func(p *UserPreferences)
+	}{
+		{"email interval", UserPreferencesUpdate{EmailInterval: &interval}, "EmailInterval", func(p *UserPreferences) { p.EmailInterval = 0 }},
+		{"time zone", UserPreferencesUpdate{TimeZone: &zone}, "TimeZone", func(p *UserPreferences) { p.TimeZone = zone }},
+		{"locale", UserPreferencesUpdate{Locale: &locale}, "Locale", func(p *UserPreferences) { p.Locale = locale }},
+		{"notify download", UserPreferencesUpdate{NotifyDownload: &no}, "DefaultDownloadNotify", func(p *UserPreferences) { p.NotifyDownload = false }},
+		{"notify upload", UserPreferencesUpdate{NotifyUpload: &yes}, "DefaultUploadNotify", func(p *UserPreferences) { p.NotifyUpload = true }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The fake keeps the preferences as JSON, merging each PATCH into them.
+			stored := map[string]json.RawMessage{}
+			data, _ := json.Marshal(initial)
+			json.Unmarshal(data, &stored)
+
+			var sent map[string]json.RawMessage
+			fakeAPI(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/sf/v3/Users(u1)/Preferences" {
+					http.NotFound(w, r)
+					return
+				}
+				if r.Method == "PATCH" {
+					if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+						t.Error(err)
+					}
+					for k, v := range sent {
+						stored[k] = v
+					}
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+				writeJSON(t, w, stored)
+			}))
+
+			ctx := context.Background()
+			if err := UpdateUserPreferences(ctx, "u1", tt.update); err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := sent[tt.key]; len(sent) != 1 || !ok {
+				t.Errorf("PATCH body %v, want only %s", sent, tt.key)
+			}
+
+			got, err := GetUserPreferences(ctx, "u1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := initial
+			tt.want(&want)
+			if !reflect.DeepEqual(*got, want) {
+				t.Errorf("preferences read back as %+v, want %+v", *got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package go-sharefile
+
+import (
+	"context"
+	"time"
+)
+
+// Changed reports whether anything below a folder has changed since the given time, using its ProgenyEditDate
+// rather than walking the tree.
+func Changed(ctx context.Context, folderID string, since time.Time) (bool, error) {
+	folder, err := GetItemByID(ctx, folderID, NewQuery().Select("Id", "ProgenyEditDate"))
+	if err != nil {
+		return false, err
+	}
+
+	return folder.ProgenyEditDate.After(since), nil
+}
+
+// WatchFolder polls a folder every interval and calls fn whenever its ProgenyEditDate advances, once per change no
+// matter how many polls see it. An interval that isn't positive polls every second. Errors from the API are logged
+// and polling carries on. WatchFolder returns when ctx is done.
+func WatchFolder(ctx context.Context, folderID string, interval time.Duration, fn func(*Item)) {
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	q := NewQuery().Select("Id", "Name", "ProgenyEditDate")
+
+	var last time.Time
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		folder, err := GetItemByID(ctx, folderID, q)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			Logger.Printf("watching folder %s: %v", folderID, err)
+		case err != nil:
+		case last.IsZero():
+			// The first poll only sets the baseline.
+			last = folder.ProgenyEditDate
+		case folder.ProgenyEditDate.After(last):
+			last = folder.ProgenyEditDate
+			fn(folder)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
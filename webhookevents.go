@@ -0,0 +1,196 @@
+package go-sharefile
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Headers ShareFile sends with webhook notifications. The signature is the base64 HMAC-SHA256, keyed with the
+// subscription's signing key, of the timestamp header, a period and the body.
+const (
+	WebhookSignatureHeader = "X-Sharefile-Signature"
+	WebhookTimestampHeader = "X-Sharefile-Timestamp"
+)
+
+// WebhookTolerance is how far a notification's timestamp may be from the local clock before ParseWebhookEvent refuses
+// it as a possible replay.
+var WebhookTolerance = 5 * time.Minute
+
+// Errors returned by ParseWebhookEvent. The signature is checked before the body is decoded, so a body altered in
+// transit fails with ErrInvalidSignature rather than ErrMalformedWebhook.
+var (
+	ErrInvalidSignature = errors.New("sharefile: invalid webhook signature")
+	ErrStaleWebhook     = errors.New("sharefile: webhook timestamp outside the tolerance")
+	ErrMalformedWebhook = errors.New("sharefile: malformed webhook body")
+)
+
+// Largest webhook body ParseWebhookEvent reads, internal package use.
+const maxWebhookBody = 1 << 20
+
+// WebhookNotification is a verified webhook notification. Exactly one of ItemUploaded, ItemDeleted and ShareAccessed
+// is set for the events the package knows; for others all are nil and the event is left in Raw to decode.
+type WebhookNotification struct {
+	SubscriptionID string
+	Event          WebhookEvent
+	Timestamp      time.Time
+
+	ItemUploaded  *ItemUploaded
+	ItemDeleted   *ItemDeleted
+	ShareAccessed *ShareAccessed
+
+	Raw json.RawMessage
+}
+
+// ItemUploaded describes a File.Upload event.
+type ItemUploaded struct {
+	ItemID     string
+	ParentID   string
+	Name       string
+	Size       int64
+	UploadedBy string
+}
+
+// ItemDeleted describes a File.Delete event.
+type ItemDeleted struct {
+	ItemID    string
+	ParentID  string
+	Name      string
+	DeletedBy string
+}
+
+// ShareAccessed describes a Share.Access event. Email and AliasID are empty for anonymous recipients.
+type ShareAccessed struct {
+	ShareID   string
+	ItemID    string
+	AliasID   string
+	Email     string
+	IPAddress string
+}
+
+// Webhook notification envelope as sent by ShareFile, internal package use.
+type webhookEnvelope struct {
+	SubscriptionID string `json:"WebhookSubscriptionId"`
+	Event          struct {
+		Timestamp     time.Time `json:"Timestamp"`
+		ResourceType  string    `json:"ResourceType"`
+		OperationName string    `json:"OperationName"`
+		Resource      struct {
+			ID            string `json:"Id"`
+			Name          string `json:"Name"`
+			FileSizeBytes int64  `json:"FileSizeBytes"`
+			Parent        struct {
+				ID string `json:"Id"`
+			} `json:"Parent"`
+			Item struct {
+				ID string `json:"Id"`
+			} `json:"Item"`
+		} `json:"Resource"`
+		Principal struct {
+			Email   string `json:"Email"`
+			AliasID string `json:"AliasId"`
+		} `json:"Principal"`
+		IPAddress string `json:"IPAddress"`
+	} `json:"Event"`
+}
+
+// ParseWebhookEvent verifies a webhook notification against the subscription's signing key and decodes it. The
+// signature is compared in constant time, and notifications whose timestamp is further than WebhookTolerance from
+// now are refused. A bad signature fails with ErrInvalidSignature, a stale timestamp with ErrStaleWebhook and a body
+// that can't be decoded with ErrMalformedWebhook. The request body is left readable for the caller.
+func ParseWebhookEvent(r *http.Request, signingKey []byte) (*WebhookNotification, error) {
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxWebhookBody+1))
+	if err != nil {
+		return nil, err
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if len(body) > maxWebhookBody {
+		return nil, fmt.Errorf("sharefile: webhook body over %d bytes: %w", maxWebhookBody, ErrMalformedWebhook)
+	}
+
+	stamp := r.Header.Get(WebhookTimestampHeader)
+	if !validWebhookSignature(signingKey, stamp, body, r.Header.Get(WebhookSignatureHeader)) {
+		return nil, ErrInvalidSignature
+	}
+
+	secs, err := strconv.ParseInt(stamp, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("sharefile: webhook timestamp %q: %w", stamp, ErrStaleWebhook)
+	}
+	sent := time.Unix(secs, 0)
+	if d := time.Since(sent); d > WebhookTolerance || d < -WebhookTolerance {
+		return nil, fmt.Errorf("sharefile: webhook sent %s: %w", sent.UTC().Format(time.RFC3339), ErrStaleWebhook)
+	}
+
+	var env webhookEnvelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		return nil, fmt.Errorf("sharefile: %v: %w", err, ErrMalformedWebhook)
+	}
+	if env.Event.ResourceType == "" || env.Event.OperationName == "" {
+		return nil, fmt.Errorf("sharefile: webhook body names no event: %w", ErrMalformedWebhook)
+	}
+
+	e := env.Event
+	n := &WebhookNotification{
+		SubscriptionID: env.SubscriptionID,
+		Event:          WebhookEvent(e.ResourceType + "." + e.OperationName),
+		Timestamp:      e.Timestamp,
+		Raw:            json.RawMessage(body),
+	}
+	if n.Timestamp.IsZero() {
+		n.Timestamp = sent
+	}
+
+	switch n.Event {
+	case EventFileUpload:
+		n.ItemUploaded = &ItemUploaded{
+			ItemID:     e.Resource.ID,
+			ParentID:   e.Resource.Parent.ID,
+			Name:       e.Resource.Name,
+			Size:       e.Resource.FileSizeBytes,
+			UploadedBy: e.Principal.Email,
+		}
+	case EventFileDelete:
+		n.ItemDeleted = &ItemDeleted{
+			ItemID:    e.Resource.ID,
+			ParentID:  e.Resource.Parent.ID,
+			Name:      e.Resource.Name,
+			DeletedBy: e.Principal.Email,
+		}
+	case EventShareAccess:
+		n.ShareAccessed = &ShareAccessed{
+			ShareID:   e.Resource.ID,
+			ItemID:    e.Resource.Item.ID,
+			AliasID:   e.Principal.AliasID,
+			Email:     e.Principal.Email,
+			IPAddress: e.IPAddress,
+		}
+	}
+
+	return n, nil
+}
+
+// Reports whether a webhook signature matches the timestamp and body, comparing in constant time, internal package
+// use.
+func validWebhookSignature(key []byte, stamp string, body []byte, signature string) bool {
+	got, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil || len(got) == 0 || stamp == "" {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(stamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return hmac.Equal(got, mac.Sum(nil))
+}
@@ -0,0 +1,134 @@
+package go-sharefile
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// A handler for a webhook subscription, refusing notifications that aren't from ShareFile.
+func ExampleParseWebhookEvent() {
+	key := []byte("subscription signing key")
+
+	http.HandleFunc("/sharefile", func(w http.ResponseWriter, r *http.Request) {
+		n, err := ParseWebhookEvent(r, key)
+		if errors.Is(err, ErrInvalidSignature) || errors.Is(err, ErrStaleWebhook) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+
+		if n.ItemUploaded != nil {
+			log.Printf("%s uploaded %s to folder %s", n.ItemUploaded.UploadedBy, n.ItemUploaded.Name, n.ItemUploaded.ParentID)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// Builds a webhook notification request for body, signed with key as of sent.
+func webhookRequest(key []byte, sent time.Time, body string) *http.Request {
+	stamp := strconv.FormatInt(sent.Unix(), 10)
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(stamp + "." + body))
+
+	r := httptest.NewRequest("POST", "/sharefile", strings.NewReader(body))
+	r.Header.Set(WebhookTimestampHeader, stamp)
+	r.Header.Set(WebhookSignatureHeader, base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+	return r
+}
+
+const uploadNotification = `{"WebhookSubscriptionId":"ws1","Event":{"Timestamp":"2025-06-01T12:00:00Z",` +
+	`"ResourceType":"File","OperationName":"Upload","Resource":{"Id":"fi1","Name":"report.pdf",` +
+	`"FileSizeBytes":5000000000,"Parent":{"Id":"fo1"}},"Principal":{"Email":"pat@example.com"}}}`
+
+func TestParseWebhookEvent(t *testing.T) {
+	key := []byte("secret")
+
+	r := webhookRequest(key, time.Now(), uploadNotification)
+	n, err := ParseWebhookEvent(r, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := ItemUploaded{ItemID: "fi1", ParentID: "fo1", Name: "report.pdf", Size: 5000000000, UploadedBy: "pat@example.com"}
+	if n.Event != EventFileUpload || n.SubscriptionID != "ws1" || n.ItemUploaded == nil || *n.ItemUploaded != want {
+		t.Errorf("ParseWebhookEvent = %+v, upload %+v", n, n.ItemUploaded)
+	}
+	if n.ItemDeleted != nil || n.ShareAccessed != nil {
+		t.Errorf("upload notification decoded as other events too: %+v", n)
+	}
+	if body, _ := ioutil.ReadAll(r.Body); string(body) != uploadNotification {
+		t.Errorf("body left as %q", body)
+	}
+}
+
+func TestParseWebhookEventRefused(t *testing.T) {
+	key := []byte("secret")
+	now := time.Now()
+
+	tests := []struct {
+		name string
+		req  func() *http.Request
+		want error
+	}{
+		{"wrong key", func() *http.Request {
+			return webhookRequest([]byte("other"), now, uploadNotification)
+		}, ErrInvalidSignature},
+		{"altered body", func() *http.Request {
+			r := webhookRequest(key, now, uploadNotification)
+			r.Body = ioutil.NopCloser(strings.NewReader(strings.Replace(uploadNotification, "fo1", "fo2", 1)))
+			return r
+		}, ErrInvalidSignature},
+		{"altered timestamp", func() *http.Request {
+			r := webhookRequest(key, now, uploadNotification)
+			r.Header.Set(WebhookTimestampHeader, strconv.FormatInt(now.Unix()+1, 10))
+			return r
+		}, ErrInvalidSignature},
+		{"no signature", func() *http.Request {
+			r := webhookRequest(key, now, uploadNotification)
+			r.Header.Del(WebhookSignatureHeader)
+			return r
+		}, ErrInvalidSignature},
+		{"signature not base64", func() *http.Request {
+			r := webhookRequest(key, now, uploadNotification)
+			r.Header.Set(WebhookSignatureHeader, "not base64!")
+			return r
+		}, ErrInvalidSignature},
+		{"stale", func() *http.Request {
+			return webhookRequest(key, now.Add(-WebhookTolerance-time.Minute), uploadNotification)
+		}, ErrStaleWebhook},
+		{"from the future", func() *http.Request {
+			return webhookRequest(key, now.Add(WebhookTolerance+time.Minute), uploadNotification)
+		}, ErrStaleWebhook},
+		{"not JSON", func() *http.Request {
+			return webhookRequest(key, now, `{"WebhookSubscriptionId":`)
+		}, ErrMalformedWebhook},
+		{"no event", func() *http.Request {
+			return webhookRequest(key, now, `{"WebhookSubscriptionId":"ws1"}`)
+		}, ErrMalformedWebhook},
+		{"too large", func() *http.Request {
+			return webhookRequest(key, now, `{"Padding":"`+strings.Repeat("x", maxWebhookBody)+`"}`)
+		}, ErrMalformedWebhook},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := ParseWebhookEvent(tt.req(), key)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("ParseWebhookEvent = %+v, %v, want %v", n, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package go-sharefile
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// WebhookEvent names an event a webhook subscription is notified of, as the kind of resource and the operation on it.
+type WebhookEvent string
+
+// Events webhooks can subscribe to.
+const (
+	EventFileUpload   WebhookEvent = "File.Upload"
+	EventFileDownload WebhookEvent = "File.Download"
+	EventFileDelete   WebhookEvent = "File.Delete"
+	EventShareAccess  WebhookEvent = "Share.Access"
+)
+
+// Kinds of resource a webhook subscription watches.
+const (
+	WebhookFolder  = "Folder"
+	WebhookAccount = "Account"
+)
+
+// WebhookResource is what a webhook subscription watches: a folder and everything below it, or the whole account, for
+// which ID is left empty.
+type WebhookResource struct {
+	Type string `json:"ResourceType"`
+	ID   string `json:"ResourceId,omitempty"`
+}
+
+// Webhook is a webhook subscription. SigningKey is the secret ShareFile signs its notifications with; keep it to
+// verify them with ParseWebhookEvent.
+type Webhook struct {
+	ID         string
+	URL        string
+	Events     []WebhookEvent
+	Resource   WebhookResource
+	SigningKey string
+}
+
+// Event of a webhook subscription as the API represents it, internal package use.
+type webhookEventBody struct {
+	ResourceType  string `json:"ResourceType"`
+	OperationName string `json:"OperationName"`
+}
+
+// Struct for use in webhook subscription POST activities, and as returned by the API
+type webhookBody struct {
+	ID                  string             `json:"Id,omitempty"`
+	SubscriptionContext WebhookResource    `json:"SubscriptionContext"`
+	WebhookURL          string             `json:"WebhookUrl"`
+	Events              []webhookEventBody `json:"Events"`
+	SigningKey          string             `json:"SigningKey,omitempty"`
+}
+
+// Returns the subscription the API describes, internal package use.
+func (b *webhookBody) webhook() Webhook {
+	w := Webhook{
+		ID:         b.ID,
+		URL:        b.WebhookURL,
+		Resource:   b.SubscriptionContext,
+		SigningKey: b.SigningKey,
+	}
+	for _, e := range b.Events {
+		w.Events = append(w.Events, WebhookEvent(e.ResourceType+"."+e.OperationName))
+	}
+	return w
+}
+
+// Collection response wrapping a list of webhook subscriptions, internal package use.
+type webhookFeed struct {
+	Webhooks []webhookBody `json:"value"`
+}
+
+// CreateWebhook subscribes targetURL to events on a resource and returns the subscription, with the key its
+// notifications are signed with. The target must be an https URL.
+func CreateWebhook(ctx context.Context, targetURL string, events []WebhookEvent, resource WebhookResource) (*Webhook, error) {
+	u, err := url.Parse(targetURL)
+	if err != nil {
+		return nil, fmt.Errorf("sharefile: webhook URL %q: %w", targetURL, err)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return nil, fmt.Errorf("sharefile: webhook URL %q is not an https URL", targetURL)
+	}
+	if len(events) == 0 {
+		return nil, fmt.Errorf("sharefile: no events to subscribe %s to", targetURL)
+	}
+
+	body := webhookBody{SubscriptionContext: resource, WebhookURL: targetURL}
+	for _, e := range events {
+		parts := strings.SplitN(string(e), ".", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("sharefile: webhook event %q isn't of the form Resource.Operation", e)
+		}
+		body.Events = append(body.Events, webhookEventBody{ResourceType: parts[0], OperationName: parts[1]})
+	}
+
+	var created webhookBody
+	if err := call(ctx, "POST", "/sf/v3/WebhookSubscriptions", body, &created); err != nil {
+		return nil, err
+	}
+
+	w := created.webhook()
+	return &w, nil
+}
+
+// Webhooks returns the webhook subscriptions of the current user.
+func Webhooks(ctx context.Context) ([]Webhook, error) {
+	var feed webhookFeed
+	if err := call(ctx, "GET", "/sf/v3/WebhookSubscriptions", nil, &feed); err != nil {
+		return nil, err
+	}
+
+	webhooks := make([]Webhook, 0, len(feed.Webhooks))
+	for i := range feed.Webhooks {
+		webhooks = append(webhooks, feed.Webhooks[i].webhook())
+	}
+
+	return webhooks, nil
+}
+
+// DeleteWebhook removes a webhook subscription, after which no more notifications are sent for it.
+func DeleteWebhook(ctx context.Context, id string) error {
+	return call(ctx, "DELETE", fmt.Sprintf("/sf/v3/WebhookSubscriptions(%s)", id), nil, nil)
+}
@@ -0,0 +1,152 @@
+package go-sharefile
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Kinds of storage zone.
+const (
+	// ZoneTypePublic zones are managed by Citrix in its cloud.
+	ZoneTypePublic = "Public"
+	// ZoneTypePrivate zones run on a StorageZones Controller the customer manages.
+	ZoneTypePrivate = "Private"
+)
+
+// Zone is a storage zone, where the files of an account are stored. Address is the zone's host, for zones that report
+// one. IsDefault isn't part of the API: Zones sets it on the zone new folders of the current user go to.
+type Zone struct {
+	ID        string `json:"Id"`
+	Name      string `json:"Name"`
+	ZoneType  string `json:"ZoneType"`
+	Address   string `json:"Address"`
+	IsDefault bool   `json:"-"`
+}
+
+// IsCustomerManaged reports whether the zone runs on the customer's own infrastructure rather than in the Citrix cloud.
+func (z *Zone) IsCustomerManaged() bool {
+	return z.ZoneType != ZoneTypePublic
+}
+
+// Collection response wrapping a list of zones, internal package use.
+type zoneFeed struct {
+	Zones []Zone `json:"value"`
+}
+
+// Zones returns the storage zones of the account, with the current user's default zone marked. When the default
+// can't be looked up, no zone is marked.
+func Zones(ctx context.Context) ([]Zone, error) {
+	var feed zoneFeed
+	if err := call(ctx, "GET", "/sf/v3/Zones", nil, &feed); err != nil {
+		return nil, err
+	}
+
+	var user User
+	q := NewQuery().Select("Id", "DefaultZone/Id").Expand("DefaultZone")
+	if err := call(ctx, "GET", withQuery("/sf/v3/Users", q), nil, &user); err == nil && user.DefaultZone != nil {
+		for i := range feed.Zones {
+			feed.Zones[i].IsDefault = feed.Zones[i].ID == user.DefaultZone.ID
+		}
+	}
+
+	return feed.Zones, nil
+}
+
+// ZoneDetails is a storage zone with the operational details it reports. HeartbeatTolerance is how long, in seconds,
+// the zone may go without checking in before it is considered down; LastHeartbeat is when it last did, and is zero
+// for zones that don't report it. Metadata holds the zone's custom metadata.
+type ZoneDetails struct {
+	Zone
+	Version            string            `json:"Version"`
+	HeartbeatTolerance int               `json:"HeartbeatTolerance"`
+	LastHeartbeat      time.Time         `json:"LastPingBackDate"`
+	Metadata           map[string]string `json:"-"`
+}
+
+// IsHealthy reports whether the zone has checked in within its heartbeat tolerance. Zones that don't report
+// heartbeats are taken to be healthy.
+func (d *ZoneDetails) IsHealthy() bool {
+	if d.LastHeartbeat.IsZero() || d.HeartbeatTolerance <= 0 {
+		return true
+	}
+	return time.Since(d.LastHeartbeat) <= time.Duration(d.HeartbeatTolerance)*time.Second
+}
+
+// ZoneMetadata returns the version, heartbeat and metadata of a storage zone, as far as the zone exposes them.
+func ZoneMetadata(ctx context.Context, zoneID string) (*ZoneDetails, error) {
+	var body struct {
+		ZoneDetails
+		Entries []metadataEntry `json:"Metadata"`
+	}
+	uriPath := withQuery(fmt.Sprintf("/sf/v3/Zones(%s)", zoneID), NewQuery().Expand("Metadata"))
+	if err := call(ctx, "GET", uriPath, nil, &body); err != nil {
+		return nil, err
+	}
+
+	details := body.ZoneDetails
+	details.Metadata = make(map[string]string, len(body.Entries))
+	for _, e := range body.Entries {
+		details.Metadata[e.Name] = e.Value
+	}
+
+	return &details, nil
+}
+
+// ChangeItemZone moves an item, and everything below a folder, to another storage zone. Migrations run on the server
+// for as long as the amount of data takes, so the returned operation only reports that it started; wait for it with
+// WaitForOperation and follow individual items with ZoneMigrationProgress. Files checked out by a user aren't moved
+// until they are checked back in.
+func ChangeItemZone(ctx context.Context, itemID, targetZoneID string) (*AsyncOperation, error) {
+	params := url.Values{}
+	params.Set("zoneId", targetZoneID)
+
+	var op AsyncOperation
+	if err := call(ctx, "POST", fmt.Sprintf("/sf/v3/Items(%s)/ChangeZone?%s", itemID, params.Encode()), nil, &op); err != nil {
+		return nil, err
+	}
+
+	return &op, nil
+}
+
+// ZoneMigrationState is where an item stands in a move to another storage zone.
+type ZoneMigrationState string
+
+// States reported by ZoneMigrationProgress.
+const (
+	ZoneMigrated ZoneMigrationState = "Migrated"
+	ZonePending  ZoneMigrationState = "Pending"
+	// ZoneLocked items are checked out, which holds them back until they are checked in.
+	ZoneLocked ZoneMigrationState = "Locked"
+)
+
+// ItemZoneState is the migration state of one item below a folder being moved, with its path relative to the folder.
+type ItemZoneState struct {
+	Path  string
+	Item  Item
+	State ZoneMigrationState
+}
+
+// ZoneMigrationProgress reports, for every item below a folder, whether it has reached the target zone, is still
+// waiting, or is held back by being checked out. Walking a large tree takes one request per folder.
+func ZoneMigrationProgress(ctx context.Context, folderID, targetZoneID string) ([]ItemZoneState, error) {
+	states := []ItemZoneState{}
+	opts := ListOptions{Query: NewQuery().Expand("Zone", "LockedBy")}
+	err := Walk(ctx, folderID, opts, func(p string, item *Item) error {
+		state := ZonePending
+		switch {
+		case item.Zone != nil && item.Zone.ID == targetZoneID:
+			state = ZoneMigrated
+		case item.LockedBy != nil && item.LockedBy.ID != "":
+			state = ZoneLocked
+		}
+		states = append(states, ItemZoneState{Path: p, Item: *item, State: state})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return states, nil
+}